@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/models"
+	"fiber-app/pkg/phone"
+	"fmt"
+	"log"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+const usage = `Usage:
+  api                       Start the API server
+  api serve                 Start the API server
+  api migrate up            Apply all pending migrations
+  api migrate down [steps]  Revert the last migration, or the given number of migrations
+  api migrate status        List migrations and whether they are applied
+  api seed                  Insert the default demo messages
+  api normalize-phones      Rewrite stored message phone numbers in E.164 form`
+
+// runCommand executes a CLI subcommand
+func runCommand(command string, args []string) error {
+	switch command {
+	case "serve":
+		serve()
+		return nil
+	case "migrate":
+		return runMigrate(args)
+	case "seed":
+		return runSeed()
+	case "normalize-phones":
+		return runNormalizePhones()
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+}
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate action\n%s", usage)
+	}
+
+	if err := database.Connect(); err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp()
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migrations", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+			steps = parsed
+		}
+		reverted, err := database.MigrateDown(steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("Reverted %d migrations", reverted)
+	case "status":
+		states, err := database.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			appliedAt := "pending"
+			if state.AppliedAt != nil {
+				appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d_%-40s %s\n", state.Version, state.Name, appliedAt)
+		}
+	default:
+		return fmt.Errorf("unknown migrate action %q\n%s", args[0], usage)
+	}
+
+	return nil
+}
+
+func runSeed() error {
+	if err := database.Connect(); err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+
+	_, err := database.Seed()
+	return err
+}
+
+// runNormalizePhones converts phone numbers stored before normalization was
+// introduced to E.164. Numbers that cannot be parsed are left unchanged and
+// reported.
+func runNormalizePhones() error {
+	if err := database.Connect(); err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+	if err := cache.Connect(); err != nil {
+		log.Printf("Warning: Failed to initialize Redis: %v", err)
+	}
+
+	var updated []uint
+	invalid := 0
+	var messages []models.Message
+	result := database.DB.Select("id", "phone").FindInBatches(&messages, 500, func(tx *gorm.DB, batch int) error {
+		for _, message := range messages {
+			normalized, err := phone.Normalize(message.Phone)
+			if err != nil {
+				log.Printf("Message %d: cannot normalize phone %q: %v", message.ID, message.Phone, err)
+				invalid++
+				continue
+			}
+			if normalized == message.Phone {
+				continue
+			}
+
+			err = database.DB.Model(&models.Message{}).Where("id = ?", message.ID).
+				UpdateColumn("phone", normalized).Error
+			if err != nil {
+				return err
+			}
+			updated = append(updated, message.ID)
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to normalize phones: %v", result.Error)
+	}
+
+	if len(updated) > 0 {
+		if err := cache.DeleteMessageCache(updated...); err != nil {
+			log.Printf("Cache delete error: %v", err)
+		}
+		if err := cache.InvalidateMessageLists(); err != nil {
+			log.Printf("Cache invalidation error: %v", err)
+		}
+	}
+
+	log.Printf("Normalized %d phone numbers, %d could not be parsed", len(updated), invalid)
+	return nil
+}
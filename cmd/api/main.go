@@ -6,6 +6,8 @@ import (
 	"fiber-app/pkg/cron"
 	"fiber-app/pkg/database"
 	"fiber-app/pkg/handlers"
+	"fiber-app/pkg/leader"
+	"fiber-app/pkg/provider"
 	"log"
 	"os"
 
@@ -28,27 +30,112 @@ import (
 // @host localhost:3000
 // @BasePath /api
 func main() {
-	app := fiber.New()
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	serve()
+}
+
+func serve() {
+	// Only CSV imports may upload large bodies, every other route keeps
+	// fiber's default limit
+	app := fiber.New(fiber.Config{
+		BodyLimit: handlers.MaxUploadSize,
+	})
 
 	app.Use(cors.New())
+	app.Use(handlers.LimitBody("/api/imports/messages"))
 
 	if err := database.Connect(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
-	// Initialize Redis connection
+	// Apply pending migrations unless disabled, existing data is never dropped
+	if os.Getenv("DB_AUTO_MIGRATE") != "false" {
+		applied, err := database.MigrateUp()
+		if err != nil {
+			log.Fatalf("Failed to apply database migrations: %v", err)
+		}
+		log.Printf("Applied %d database migrations", applied)
+	}
+
+	// Imports run in memory, so jobs of a stopped instance never finish
+	if recovered, err := handlers.RecoverImportJobs(); err != nil {
+		log.Printf("Warning: Failed to recover interrupted import jobs: %v", err)
+	} else if recovered > 0 {
+		log.Printf("Marked %d interrupted import jobs as failed", recovered)
+	}
+
+	// Initialize Redis connection. Without leader election Redis is only a
+	// cache; with it, no instance could ever become leader and send.
 	if err := cache.Connect(); err != nil {
+		if leader.Enabled() {
+			log.Fatalf("Failed to initialize Redis, required for leader election (set LEADER_ELECTION_ENABLED=false to run a single instance without it): %v", err)
+		}
 		log.Printf("Warning: Failed to initialize Redis: %v", err)
 	}
 
+	// Refuse to start with a provider that cannot send, rather than
+	// queueing messages that are never delivered
+	if _, err := provider.FromEnv(); err != nil {
+		log.Fatalf("Invalid message provider configuration: %v", err)
+	}
+
 	app.Get("/swagger/*", swagger.New(swagger.Config{
 		URL:         "/swagger/doc.json",
 		DeepLinking: true,
 	}))
 
 	api := app.Group("/api")
-	api.Post("/messages", handlers.CreateMessage)
+	api.Post("/messages", handlers.Idempotency, handlers.CreateMessage)
 	api.Get("/messages", handlers.GetMessages)
+	api.Post("/messages/bulk", handlers.Idempotency, handlers.CreateMessagesBulk)
+	api.Get("/messages/dead", handlers.GetDeadMessages)
+	api.Get("/messages/provider/:messageId", handlers.GetMessageByProviderID)
+	api.Get("/messages/:id", handlers.GetMessage)
+	api.Patch("/messages/:id", handlers.UpdateMessage)
+	api.Delete("/messages/:id", handlers.DeleteMessage)
+	api.Post("/messages/:id/cancel", handlers.CancelMessage)
+	api.Post("/messages/:id/requeue", handlers.RequeueMessage)
+	api.Post("/templates", handlers.CreateTemplate)
+	api.Get("/templates", handlers.GetTemplates)
+	api.Get("/templates/:id", handlers.GetTemplate)
+	api.Put("/templates/:id", handlers.UpdateTemplate)
+	api.Delete("/templates/:id", handlers.DeleteTemplate)
+	api.Put("/templates/:id/variants/:locale", handlers.SetTemplateVariant)
+	api.Delete("/templates/:id/variants/:locale", handlers.DeleteTemplateVariant)
+	api.Post("/contacts", handlers.CreateContact)
+	api.Get("/contacts", handlers.GetContacts)
+	api.Get("/contacts/:id", handlers.GetContact)
+	api.Put("/contacts/:id", handlers.UpdateContact)
+	api.Delete("/contacts/:id", handlers.DeleteContact)
+	api.Post("/groups", handlers.CreateGroup)
+	api.Get("/groups", handlers.GetGroups)
+	api.Get("/groups/:id", handlers.GetGroup)
+	api.Put("/groups/:id", handlers.UpdateGroup)
+	api.Delete("/groups/:id", handlers.DeleteGroup)
+	api.Get("/groups/:id/contacts", handlers.GetGroupContacts)
+	api.Post("/groups/:id/contacts", handlers.AddGroupContacts)
+	api.Delete("/groups/:id/contacts/:contactId", handlers.RemoveGroupContact)
+	api.Post("/campaigns", handlers.CreateCampaign)
+	api.Get("/campaigns", handlers.GetCampaigns)
+	api.Get("/campaigns/:id", handlers.GetCampaign)
+	api.Post("/campaigns/:id/recipients", handlers.UploadCampaignRecipients)
+	api.Post("/campaigns/:id/start", handlers.StartCampaign)
+	api.Post("/campaigns/:id/pause", handlers.PauseCampaign)
+	api.Post("/campaigns/:id/resume", handlers.ResumeCampaign)
+	api.Post("/campaigns/:id/cancel", handlers.CancelCampaign)
+	api.Post("/suppressions", handlers.CreateSuppression)
+	api.Get("/suppressions", handlers.GetSuppressions)
+	api.Delete("/suppressions/:id", handlers.DeleteSuppression)
+	api.Post("/imports/messages", handlers.ImportMessages)
+	api.Get("/imports", handlers.GetImportJobs)
+	api.Get("/imports/:id", handlers.GetImportJob)
+	api.Get("/imports/:id/errors", handlers.GetImportErrors)
 	api.Post("/cron/start", handlers.StartCronJob)
 	api.Post("/cron/stop", handlers.StopCronJob)
 	api.Get("/cron/status", handlers.GetCronStatus)
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyRecord is the stored outcome of a request made with an
+// Idempotency-Key. Completed is false while the first request is still
+// being handled.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+func idempotencyKey(scope, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s", scope, key)
+}
+
+// ReserveIdempotencyKey claims key for a request with the given fingerprint.
+// It returns nil if the key was free and is now reserved, or the record
+// stored by an earlier request with the same key.
+func ReserveIdempotencyKey(scope, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	ctx, cancel := context.WithTimeout(Ctx, time.Second*5)
+	defer cancel()
+
+	pending, err := json.Marshal(IdempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal idempotency record: %v", err)
+	}
+
+	redisKey := idempotencyKey(scope, key)
+	reserved, err := RedisClient.SetNX(ctx, redisKey, pending, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %v", err)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	data, err := RedisClient.Get(ctx, redisKey).Bytes()
+	if err == redis.Nil {
+		// The record expired between SETNX and GET, try once more
+		reserved, err = RedisClient.SetNX(ctx, redisKey, pending, ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %v", err)
+		}
+		if reserved {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("idempotency key %s changed while reserving", key)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency record: %v", err)
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotency record: %v", err)
+	}
+
+	return &record, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request holding key so
+// later replays receive the same response
+func CompleteIdempotencyKey(scope, key string, record IdempotencyRecord, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(Ctx, time.Second*5)
+	defer cancel()
+
+	record.Completed = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %v", err)
+	}
+
+	if err := RedisClient.Set(ctx, idempotencyKey(scope, key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotency record: %v", err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey frees a reserved key so the request can be retried
+func ReleaseIdempotencyKey(scope, key string) error {
+	ctx, cancel := context.WithTimeout(Ctx, time.Second*5)
+	defer cancel()
+
+	if err := RedisClient.Del(ctx, idempotencyKey(scope, key)).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	messageListVersionKey = "messages:list:version"
+	messageListTTL        = 30 * time.Second
+)
+
+// MessageListKey returns the cache key for a normalized list query. The key
+// embeds the current list version, so InvalidateMessageLists makes every
+// previously cached page unreachable at once.
+func MessageListKey(query string) (string, error) {
+	ctx, cancel := context.WithTimeout(Ctx, time.Second*5)
+	defer cancel()
+
+	version, err := RedisClient.Get(ctx, messageListVersionKey).Int64()
+	if err != nil && err != redis.Nil {
+		return "", fmt.Errorf("failed to get message list version: %v", err)
+	}
+
+	hash := sha1.Sum([]byte(query))
+	return fmt.Sprintf("messages:list:%d:%s", version, hex.EncodeToString(hash[:])), nil
+}
+
+// GetMessageList loads a cached list response into dest and reports whether
+// it was found
+func GetMessageList(key string, dest interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(Ctx, time.Second*5)
+	defer cancel()
+
+	data, err := RedisClient.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get message list cache: %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(data), dest); err != nil {
+		return false, fmt.Errorf("failed to unmarshal message list: %v", err)
+	}
+
+	return true, nil
+}
+
+// SetMessageList stores a list response for a short time
+func SetMessageList(key string, data interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message list: %v", err)
+	}
+
+	if err := RedisClient.Set(Ctx, key, jsonData, messageListTTL).Err(); err != nil {
+		return fmt.Errorf("failed to set message list cache: %v", err)
+	}
+
+	return nil
+}
+
+// InvalidateMessageLists expires all cached list responses. It must be
+// called whenever messages are created or change status.
+func InvalidateMessageLists() error {
+	ctx, cancel := context.WithTimeout(Ctx, time.Second*5)
+	defer cancel()
+
+	if err := RedisClient.Incr(ctx, messageListVersionKey).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate message lists: %v", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"fiber-app/pkg/models"
 	"fmt"
 	"os"
 	"time"
@@ -16,11 +17,84 @@ var (
 )
 
 type MessageCache struct {
-	ID        uint   `json:"id"`
-	MessageID string `json:"message_id"`
-	Status    bool   `json:"status"`
-	Content   string `json:"content"`
-	Phone     string `json:"phone"`
+	ID            uint                   `json:"id"`
+	MessageID     string                 `json:"message_id"`
+	Status        models.MessageStatus   `json:"status"`
+	Category      models.MessageCategory `json:"category"`
+	Content       string                 `json:"content"`
+	Encoding      string                 `json:"encoding"`
+	Segments      int                    `json:"segments"`
+	TemplateID    *uint                  `json:"template_id,omitempty"`
+	Locale        string                 `json:"locale,omitempty"`
+	ContactID     *uint                  `json:"contact_id,omitempty"`
+	CampaignID    *uint                  `json:"campaign_id,omitempty"`
+	Phone         string                 `json:"phone"`
+	Priority      int                    `json:"priority"`
+	Attempts      int                    `json:"attempts"`
+	LastError     string                 `json:"last_error,omitempty"`
+	SendAt        *time.Time             `json:"send_at,omitempty"`
+	ExpiresAt     *time.Time             `json:"expires_at,omitempty"`
+	NextAttemptAt *time.Time             `json:"next_attempt_at,omitempty"`
+	SentAt        *time.Time             `json:"sent_at,omitempty"`
+	FailedAt      *time.Time             `json:"failed_at,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// NewMessageCache copies the cached fields of a message
+func NewMessageCache(message models.Message) MessageCache {
+	return MessageCache{
+		ID:            message.ID,
+		MessageID:     message.MessageID,
+		Status:        message.Status,
+		Category:      message.Category,
+		Content:       message.Content,
+		Encoding:      message.Encoding,
+		Segments:      message.Segments,
+		TemplateID:    message.TemplateID,
+		Locale:        message.Locale,
+		ContactID:     message.ContactID,
+		CampaignID:    message.CampaignID,
+		Phone:         message.Phone,
+		Priority:      message.Priority,
+		Attempts:      message.Attempts,
+		LastError:     message.LastError,
+		SendAt:        message.SendAt,
+		ExpiresAt:     message.ExpiresAt,
+		NextAttemptAt: message.NextAttemptAt,
+		SentAt:        message.SentAt,
+		FailedAt:      message.FailedAt,
+		CreatedAt:     message.CreatedAt,
+		UpdatedAt:     message.UpdatedAt,
+	}
+}
+
+// ToMessage converts the cached data back into a message
+func (m MessageCache) ToMessage() models.Message {
+	return models.Message{
+		ID:            m.ID,
+		MessageID:     m.MessageID,
+		Status:        m.Status,
+		Category:      m.Category,
+		Content:       m.Content,
+		Encoding:      m.Encoding,
+		Segments:      m.Segments,
+		TemplateID:    m.TemplateID,
+		Locale:        m.Locale,
+		ContactID:     m.ContactID,
+		CampaignID:    m.CampaignID,
+		Phone:         m.Phone,
+		Priority:      m.Priority,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		SendAt:        m.SendAt,
+		ExpiresAt:     m.ExpiresAt,
+		NextAttemptAt: m.NextAttemptAt,
+		SentAt:        m.SentAt,
+		FailedAt:      m.FailedAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
 }
 
 func Connect() error {
@@ -52,9 +126,55 @@ func SetMessageCache(messageID uint, data MessageCache) error {
 		return fmt.Errorf("failed to set message cache: %v", err)
 	}
 
+	// Index by provider message ID so the message can be looked up by either ID
+	if data.MessageID != "" {
+		err = RedisClient.Set(Ctx, providerMessageKey(data.MessageID), messageID, time.Hour).Err()
+		if err != nil {
+			return fmt.Errorf("failed to set provider message index: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteMessageCache removes cached messages, e.g. after their status changed
+func DeleteMessageCache(messageIDs ...uint) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(messageIDs))
+	for i, messageID := range messageIDs {
+		keys[i] = fmt.Sprintf("message:%d", messageID)
+	}
+
+	if err := RedisClient.Del(Ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete message cache: %v", err)
+	}
+
 	return nil
 }
 
+// GetMessageIDByProviderID returns the internal ID cached for a provider
+// message ID, or 0 if it is not cached
+func GetMessageIDByProviderID(providerMessageID string) (uint, error) {
+	ctx, cancel := context.WithTimeout(Ctx, time.Second*5)
+	defer cancel()
+
+	id, err := RedisClient.Get(ctx, providerMessageKey(providerMessageID)).Uint64()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get provider message index: %v", err)
+	}
+
+	return uint(id), nil
+}
+
+func providerMessageKey(providerMessageID string) string {
+	return fmt.Sprintf("message:provider:%s", providerMessageID)
+}
+
 func GetMessageCache(messageID uint) (*MessageCache, error) {
 	key := fmt.Sprintf("message:%d", messageID)
 	data, err := RedisClient.Get(Ctx, key).Result()
@@ -0,0 +1,59 @@
+package cron
+
+import (
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// activateCampaigns moves scheduled campaigns whose start time has come to
+// running, so their messages can be claimed
+func activateCampaigns() {
+	now := time.Now()
+	result := database.DB.Model(&models.Campaign{}).
+		Where("status = ? AND (start_at IS NULL OR start_at <= ?)", models.CampaignStatusScheduled, now).
+		Update("status", models.CampaignStatusRunning)
+	if result.Error != nil {
+		errors.LogError(errors.NewDatabaseError("Error activating campaigns", result.Error))
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		description := fmt.Sprintf("Started %d scheduled campaigns", result.RowsAffected)
+		log.Println(description)
+		logCronOperation("CAMPAIGN_START", nil, int(result.RowsAffected), true, description)
+	}
+}
+
+// completeCampaigns marks running campaigns without queued or sending
+// messages as completed
+func completeCampaigns() {
+	result := database.DB.Model(&models.Campaign{}).
+		Where("status = ?", models.CampaignStatusRunning).
+		Where("NOT EXISTS (?)", pendingMessages().Select("1").Where("messages.campaign_id = campaigns.id")).
+		Updates(map[string]interface{}{
+			"status":       models.CampaignStatusCompleted,
+			"completed_at": time.Now(),
+		})
+	if result.Error != nil {
+		errors.LogError(errors.NewDatabaseError("Error completing campaigns", result.Error))
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		description := fmt.Sprintf("Completed %d campaigns", result.RowsAffected)
+		log.Println(description)
+		logCronOperation("CAMPAIGN_COMPLETE", nil, int(result.RowsAffected), true, description)
+	}
+}
+
+// runningCampaigns selects the IDs of running campaigns. Messages of
+// campaigns in any other status are not claimed.
+func runningCampaigns() *gorm.DB {
+	return database.DB.Model(&models.Campaign{}).Select("id").Where("status = ?", models.CampaignStatusRunning)
+}
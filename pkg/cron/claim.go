@@ -0,0 +1,214 @@
+package cron
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultLeaseDuration = 5 * time.Minute
+
+// claimMessages atomically moves up to limit due messages from queued to
+// sending and stamps them with a claim token and lease. Rows locked by
+// another replica are skipped, so each message is claimed by one worker.
+func claimMessages(limit int) ([]models.Message, error) {
+	token, err := newClaimToken()
+	if err != nil {
+		return nil, errors.NewCronError("Error generating claim token", err)
+	}
+
+	var messages []models.Message
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		campaigns := runningCampaigns()
+		due := func(db *gorm.DB) *gorm.DB {
+			return db.Where("status = ?", models.MessageStatusQueued).
+				Where("send_at IS NULL OR send_at <= ?", now).
+				Where("expires_at IS NULL OR expires_at > ?", now).
+				Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+				Where("campaign_id IS NULL OR campaign_id IN (?)", campaigns)
+		}
+
+		// No index can serve the aged priority order, so sorting under
+		// FOR UPDATE would lock every due row. The candidates are picked
+		// with a plain read and only they are locked, by primary key. A
+		// candidate claimed or changed by someone else in between is
+		// skipped until the next cycle.
+		var candidates []uint
+		result := tx.Model(&models.Message{}).Scopes(due).
+			Order(priorityOrder(now)).Limit(limit).Pluck("id", &candidates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		result = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Scopes(due).Where("id IN ?", candidates).
+			Order(priorityOrder(now)).Find(&messages)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+
+		leaseExpiresAt := now.Add(leaseDuration)
+		_, err := models.TransitionMessages(tx, ids, models.MessageStatusQueued, models.MessageStatusSending,
+			map[string]interface{}{
+				"claim_token":      token,
+				"lease_expires_at": leaseExpiresAt,
+			})
+		if err != nil {
+			return err
+		}
+
+		for i := range messages {
+			messages[i].Status = models.MessageStatusSending
+			messages[i].ClaimToken = token
+			messages[i].LeaseExpiresAt = &leaseExpiresAt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("Error claiming messages", err)
+	}
+
+	if len(messages) > 0 {
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		uncacheMessages(ids)
+	}
+
+	return messages, nil
+}
+
+// priorityOrder sorts by priority, highest first. A message gains one
+// priority level for every aging interval it has been due, so low priority
+// messages are eventually served even under constant high priority traffic.
+func priorityOrder(now time.Time) clause.OrderBy {
+	agingSeconds := int64(priorityAgingInterval / time.Second)
+	if agingSeconds < 1 {
+		agingSeconds = 1
+	}
+
+	return clause.OrderBy{
+		Expression: clause.Expr{
+			SQL:  "priority + FLOOR(TIMESTAMPDIFF(SECOND, COALESCE(send_at, created_at), ?) / ?) DESC, created_at ASC",
+			Vars: []interface{}{now, agingSeconds},
+		},
+	}
+}
+
+// reclaimExpiredLeases returns messages whose lease expired, for example
+// because the worker holding them crashed, to the queue
+func reclaimExpiredLeases() {
+	ids, err := transitionMessages(models.MessageStatusSending, models.MessageStatusQueued,
+		database.DB.Where("lease_expires_at < ?", time.Now()),
+		map[string]interface{}{
+			"claim_token":      nil,
+			"lease_expires_at": nil,
+			"last_error":       "lease expired before delivery completed",
+		})
+	if err != nil {
+		errors.LogError(errors.NewDatabaseError("Error reclaiming expired leases", err))
+		return
+	}
+
+	if len(ids) > 0 {
+		description := fmt.Sprintf("Reclaimed %d messages with expired leases", len(ids))
+		log.Println(description)
+		logCronOperation("RECLAIM", ids, len(ids), true, description)
+	}
+}
+
+// expireMessages moves queued messages past their validity period to the
+// expired status so they are never sent late
+func expireMessages() {
+	ids, err := transitionMessages(models.MessageStatusQueued, models.MessageStatusExpired,
+		database.DB.Where("expires_at <= ?", time.Now()),
+		map[string]interface{}{"next_attempt_at": nil})
+	if err != nil {
+		errors.LogError(errors.NewDatabaseError("Error expiring messages", err))
+		return
+	}
+
+	if len(ids) > 0 {
+		description := fmt.Sprintf("Expired %d messages past their validity period", len(ids))
+		log.Println(description)
+		logCronOperation("EXPIRE", ids, len(ids), true, description)
+	}
+}
+
+// suppressMessages moves queued messages to the suppressed status when
+// their recipient is on the suppression list. Suppressions scoped to
+// marketing only hold back marketing messages.
+func suppressMessages() {
+	ids, err := transitionMessages(models.MessageStatusQueued, models.MessageStatusSuppressed,
+		database.DB.Where("EXISTS (SELECT 1 FROM suppressions WHERE suppressions.phone = messages.phone AND (suppressions.scope = ? OR messages.category = ?))",
+			models.SuppressionScopeAll, models.MessageCategoryMarketing),
+		map[string]interface{}{
+			"next_attempt_at": nil,
+			"last_error":      "recipient is on the suppression list",
+		})
+	if err != nil {
+		errors.LogError(errors.NewDatabaseError("Error suppressing messages", err))
+		return
+	}
+
+	if len(ids) > 0 {
+		description := fmt.Sprintf("Suppressed %d messages to opted-out recipients", len(ids))
+		log.Println(description)
+		logCronOperation("SUPPRESS", ids, len(ids), true, description)
+	}
+}
+
+// transitionMessages moves every message matching scope from one status to
+// another in bulk and returns the affected IDs. Cached copies and listings
+// of those messages are invalidated.
+func transitionMessages(from, to models.MessageStatus, scope *gorm.DB, updates map[string]interface{}) ([]uint, error) {
+	var ids []uint
+	if err := scope.Model(&models.Message{}).Where("status = ?", from).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if _, err := models.TransitionMessages(database.DB, ids, from, to, updates); err != nil {
+		return nil, err
+	}
+
+	uncacheMessages(ids)
+	invalidateMessageLists()
+	return ids, nil
+}
+
+// claimed scopes an update to the worker holding the message's claim
+func claimed(message *models.Message) *gorm.DB {
+	return database.DB.Where("claim_token = ?", message.ClaimToken)
+}
+
+func newClaimToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
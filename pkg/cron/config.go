@@ -0,0 +1,9 @@
+package cron
+
+import "time"
+
+const (
+	defaultBatchSize             = 2
+	defaultConcurrency           = 1
+	defaultPriorityAgingInterval = time.Minute
+)
@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// GetDeadMessages returns messages that exhausted their delivery attempts,
+// most recently failed first
+func GetDeadMessages(limit int) ([]models.Message, error) {
+	var messages []models.Message
+	result := database.DB.Where("status = ?", models.MessageStatusFailed).Order("failed_at desc").Limit(limit).Find(&messages)
+	if result.Error != nil {
+		return nil, errors.NewDatabaseError("Error fetching dead messages", result.Error)
+	}
+	return messages, nil
+}
+
+// RequeueMessage moves a permanently failed message back into the queue
+// with a fresh attempt budget
+func RequeueMessage(id uint) (*models.Message, error) {
+	var message models.Message
+	if err := database.DB.First(&message, id).Error; err == gorm.ErrRecordNotFound {
+		return nil, errors.NewNotFoundError("Message not found", err).
+			WithMetadata("messageId", id)
+	} else if err != nil {
+		return nil, errors.NewDatabaseError("Error fetching message", err).
+			WithMetadata("messageId", id)
+	}
+
+	// Only permanently failed messages may be requeued. Messages being sent
+	// may also move back to queued, but requeueing one here would send it
+	// twice.
+	if message.Status != models.MessageStatusFailed {
+		return nil, errors.NewConflictError("Only failed messages can be requeued", nil).
+			WithMetadata("messageId", id).
+			WithMetadata("status", message.Status)
+	}
+
+	message.Attempts = 0
+	message.LastError = ""
+	message.NextAttemptAt = nil
+	message.FailedAt = nil
+	message.ClaimToken = ""
+	message.LeaseExpiresAt = nil
+	err := message.UpdateStatus(database.DB, models.MessageStatusQueued, map[string]interface{}{
+		"failed_at":        nil,
+		"next_attempt_at":  nil,
+		"attempts":         0,
+		"last_error":       "",
+		"claim_token":      nil,
+		"lease_expires_at": nil,
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			appErr.WithMetadata("messageId", id)
+		}
+		return nil, err
+	}
+
+	cacheMessage(message)
+	invalidateMessageLists()
+	description := fmt.Sprintf("Message %d requeued manually", id)
+	log.Println(description)
+	logCronOperation("REQUEUE", []uint{id}, 1, true, description)
+	return &message, nil
+}
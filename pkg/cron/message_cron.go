@@ -1,44 +1,46 @@
 package cron
 
 import (
-	"bytes"
-	"encoding/json"
 	"fiber-app/pkg/cache"
+	"fiber-app/pkg/config"
 	"fiber-app/pkg/database"
 	"fiber-app/pkg/errors"
+	"fiber-app/pkg/leader"
 	"fiber-app/pkg/models"
+	"fiber-app/pkg/provider"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
 )
 
 var (
-	cronJob   *cron.Cron
-	cronMutex sync.Mutex
-	isRunning bool
-	entryID   cron.EntryID
+	cronJob         *cron.Cron
+	cronMutex       sync.Mutex
+	isRunning       bool
+	entryID         cron.EntryID
+	messageProvider provider.Provider
+	retryPolicy     RetryPolicy
+	batchSize       int
+	concurrency     int
+	leaseDuration   time.Duration
+
+	priorityAgingInterval time.Duration
 )
 
-type WebhookRequest struct {
-	To      string `json:"to"`
-	Content string `json:"content"`
-}
-
-type WebhookResponse struct {
-	Message   string `json:"message"`
-	MessageID string `json:"messageId"`
-}
-
 func init() {
-	cronJob = cron.New(cron.WithSeconds())
+	cronJob = cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
 	isRunning = false
+	retryPolicy = retryPolicyFromEnv()
+	batchSize = config.Int("CRON_BATCH_SIZE", defaultBatchSize)
+	concurrency = config.Int("CRON_CONCURRENCY", defaultConcurrency)
+	leaseDuration = config.Duration("CRON_LEASE_DURATION", defaultLeaseDuration)
+	priorityAgingInterval = config.Duration("PRIORITY_AGING_INTERVAL", defaultPriorityAgingInterval)
 }
 
 func logCronOperation(operation string, messageIDs []uint, count int, status bool, description string) {
@@ -60,155 +62,118 @@ func logCronOperation(operation string, messageIDs []uint, count int, status boo
 	}
 }
 
+// runScheduledJob sends messages only on the instance holding leadership
+func runScheduledJob() {
+	if !leader.IsLeader() {
+		return
+	}
+	updateInactiveMessages()
+}
+
 func updateInactiveMessages() {
-	var messages []models.Message
+	reclaimExpiredLeases()
+	expireMessages()
+	suppressMessages()
+	activateCampaigns()
+	completeCampaigns()
 
-	result := database.DB.Where("status = ?", false).Order("created_at asc").Limit(2).Find(&messages)
-	if result.Error != nil {
-		err := errors.NewDatabaseError("Error fetching inactive messages", result.Error)
+	messages, err := claimMessages(batchSize)
+	if err != nil {
 		errors.LogError(err)
 		return
 	}
 
 	if len(messages) == 0 {
-		log.Println("No inactive messages found")
-		logCronOperation("NO_MESSAGES", nil, 0, true, "No inactive messages found, stopping cron")
-		StopCron()
-		return
-	}
-
-	log.Printf("Processing %d messages in this cycle", len(messages))
-
-	for _, message := range messages {
-		log.Printf("Processing message ID: %d", message.ID)
-		webhookURL := os.Getenv("WEBHOOK_URL")
-		if webhookURL == "" {
-			webhookURL = "https://webhook.site/03c75f60-8d13-47f9-b11b-4181faad6ce0"
+		var pending int64
+		if err := pendingMessages().Count(&pending).Error; err != nil {
+			errors.LogError(errors.NewDatabaseError("Error counting pending messages", err))
+			return
 		}
-
-		requestBody := WebhookRequest{
-			To:      message.Phone,
-			Content: message.Content,
+		if pending > 0 {
+			log.Printf("No messages due, %d still pending", pending)
+			return
 		}
 
-		jsonData, err := json.Marshal(requestBody)
-		if err != nil {
-			err = errors.NewWebhookError("Error marshaling request", err).
-				WithMetadata("messageId", message.ID)
-			errors.LogError(err)
-			continue
-		}
+		log.Println("No inactive messages found")
 
-		req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(jsonData))
-		if err != nil {
-			err = errors.NewWebhookError("Error creating request", err).
-				WithMetadata("messageId", message.ID).
-				WithMetadata("webhookURL", webhookURL)
-			errors.LogError(err)
-			continue
+		// With leader election the cron keeps polling. Stopping it would hand
+		// leadership to the next replica, which finds the same empty queue,
+		// until no replica is left to send new messages.
+		if leader.Enabled() {
+			return
 		}
+		logCronOperation("NO_MESSAGES", nil, 0, true, "No inactive messages found, stopping cron")
+		StopCron()
+		return
+	}
 
-		req.Header.Add("Content-Type", "application/json")
-		authKey := os.Getenv("WEBHOOK_AUTH_KEY")
-		if authKey == "" {
-			authKey = "INS.me1x9uMcyYGlhKKQVPoc.bO3j9aZwRTOcA2Ywo"
-		}
-		req.Header.Add("x-ins-auth-key", authKey)
-
-		// Debug: Read and recreate request body
-		bodyBytes, _ := io.ReadAll(req.Body)
-		fmt.Println("Request Body:", string(bodyBytes))
-		req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-		fmt.Println("*************************************************") // Debug
-		fmt.Println(req)
-		fmt.Println("*************************************************") // Debug
-
-		// Service request cancelled due to IP ban
-		// Real service implementation
-		/*
-			client := &http.Client{
-				Timeout: 10 * time.Second,
-			}
-			resp, err := client.Do(req)
-			if err != nil {
-				err = errors.NewWebhookError("Error sending request", err).
-					WithMetadata("messageId", message.ID).
-					WithMetadata("webhookURL", webhookURL)
-				errors.LogError(err)
-				logCronOperation("WEBHOOK_REQUEST", []uint{message.ID}, 1, false, fmt.Sprintf("Request failed: %v", err))
-				continue
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				err = errors.NewWebhookError("Webhook request failed", fmt.Errorf("status code: %d", resp.StatusCode)).
-					WithMetadata("messageId", message.ID).
-					WithMetadata("statusCode", resp.StatusCode)
-				errors.LogError(err)
-				logCronOperation("WEBHOOK_RESPONSE", []uint{message.ID}, 1, false, fmt.Sprintf("Response status not OK: %d", resp.StatusCode))
-				continue
-			}
-
-			var response WebhookResponse
-			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-				err = errors.NewWebhookError("Error decoding response", err).
-					WithMetadata("messageId", message.ID)
-				errors.LogError(err)
-				logCronOperation("WEBHOOK_RESPONSE", []uint{message.ID}, 1, false, fmt.Sprintf("Response decode failed: %v", err))
-				continue
-			}
-		*/
-
-		// Simulated successful response
-		simulatedResponse := WebhookResponse{
-			Message:   "Message sent successfully",
-			MessageID: fmt.Sprintf("SIMULATED_MSG_%d_%d", message.ID, time.Now().Unix()),
-		}
-		simulatedResponseBytes, _ := json.Marshal(simulatedResponse)
+	log.Printf("Processing %d messages in this cycle with %d workers", len(messages), concurrency)
 
-		// Debug HTTP Response
-		fmt.Println("Response Status: 200 OK (Simulated)")
-		fmt.Println("Response Body:", string(simulatedResponseBytes))
+	results := processMessages(messages, concurrency)
+	invalidateMessageLists()
+	logRunResults(results)
+}
 
-		log.Printf("Response for message %d - Status: 200 OK, Body: %s", message.ID, string(simulatedResponseBytes))
+// invalidateMessageLists drops cached message listings after status changes
+func invalidateMessageLists() {
+	if err := cache.InvalidateMessageLists(); err != nil {
+		errors.LogError(errors.NewCacheError("Error invalidating message lists", err))
+	}
+}
 
-		var response WebhookResponse
-		if err := json.Unmarshal(simulatedResponseBytes, &response); err != nil {
-			err = errors.NewWebhookError("Error decoding response", err).
-				WithMetadata("messageId", message.ID)
-			errors.LogError(err)
-			logCronOperation("WEBHOOK_RESPONSE", []uint{message.ID}, 1, false, fmt.Sprintf("Response decode failed: %v", err))
-			continue
-		}
+// pendingMessages scopes a query to messages waiting in the queue or
+// currently claimed by a worker
+func pendingMessages() *gorm.DB {
+	return database.DB.Model(&models.Message{}).
+		Where("status IN ?", []models.MessageStatus{models.MessageStatusQueued, models.MessageStatusSending})
+}
 
-		message.Status = true
-		message.MessageID = response.MessageID
-		if err := database.DB.Save(&message).Error; err != nil {
-			err = errors.NewDatabaseError("Error updating message status", err).
-				WithMetadata("messageId", message.ID).
-				WithMetadata("webhookMessageId", response.MessageID)
-			errors.LogError(err)
-			logCronOperation("DATABASE_UPDATE", []uint{message.ID}, 1, false, fmt.Sprintf("DB update failed: %v", err))
-			continue
-		}
+// recordFailure stores a failed delivery attempt and either schedules the
+// next attempt or marks the message as permanently failed
+func recordFailure(message *models.Message, sendErr error) messageResult {
+	now := time.Now()
+	message.Attempts++
+	message.LastError = sendErr.Error()
 
-		cacheData := cache.MessageCache{
-			ID:        message.ID,
-			MessageID: response.MessageID,
-			Status:    true,
-			Content:   message.Content,
-			Phone:     message.Phone,
-		}
-		if err := cache.SetMessageCache(message.ID, cacheData); err != nil {
-			err = errors.NewCacheError("Error caching message", err).
-				WithMetadata("messageId", message.ID)
-			errors.LogError(err)
-		}
+	var err error
+	var result messageResult
+	if retryPolicy.Exhausted(message.Attempts) {
+		message.FailedAt = &now
+		message.NextAttemptAt = nil
+		result = messageResult{MessageID: message.ID, Outcome: outcomeFailed, Err: sendErr}
+		err = message.UpdateStatus(claimed(message), models.MessageStatusFailed, map[string]interface{}{
+			"attempts":         message.Attempts,
+			"last_error":       message.LastError,
+			"failed_at":        message.FailedAt,
+			"next_attempt_at":  nil,
+			"claim_token":      nil,
+			"lease_expires_at": nil,
+		})
+		log.Printf("Message %d: delivery failed permanently after %d attempts: %v", message.ID, message.Attempts, sendErr)
+	} else {
+		next := now.Add(retryPolicy.Backoff(message.Attempts))
+		message.NextAttemptAt = &next
+		result = messageResult{MessageID: message.ID, Outcome: outcomeRetry, Err: sendErr}
+		err = message.UpdateStatus(claimed(message), models.MessageStatusQueued, map[string]interface{}{
+			"attempts":         message.Attempts,
+			"last_error":       message.LastError,
+			"next_attempt_at":  message.NextAttemptAt,
+			"claim_token":      nil,
+			"lease_expires_at": nil,
+		})
+		log.Printf("Message %d: attempt %d failed, next attempt at %s: %v", message.ID, message.Attempts, next.Format(time.RFC3339), sendErr)
+	}
 
-		log.Printf("Successfully updated message %d with message_id %s", message.ID, response.MessageID)
-		logCronOperation("MESSAGE_PROCESSED", []uint{message.ID}, 1, true, fmt.Sprintf("Message processed successfully with ID: %s", response.MessageID))
+	if err != nil {
+		err = errors.NewDatabaseError("Error recording delivery failure", err).
+			WithMetadata("messageId", message.ID).
+			WithMetadata("attempts", message.Attempts)
+		errors.LogError(err)
+		return messageResult{MessageID: message.ID, Outcome: outcomeError, Err: err}
 	}
+
+	cacheMessage(*message)
+	return result
 }
 
 func StartCron() error {
@@ -219,13 +184,25 @@ func StartCron() error {
 		return nil
 	}
 
+	if messageProvider == nil {
+		p, err := provider.FromEnv()
+		if err != nil {
+			err = errors.NewCronError("Failed to create message provider", err)
+			errors.LogError(err)
+			logCronOperation("START", nil, 0, false, fmt.Sprintf("Failed to start cron: %v", err))
+			return err
+		}
+		messageProvider = p
+		log.Printf("Using message provider: %s", messageProvider.Name())
+	}
+
 	schedule := os.Getenv("CRON_SCHEDULE")
 	if schedule == "" {
 		schedule = "*/30 * * * * *"
 	}
 
 	var err error
-	entryID, err = cronJob.AddFunc(schedule, updateInactiveMessages)
+	entryID, err = cronJob.AddFunc(schedule, runScheduledJob)
 	if err != nil {
 		err = errors.NewCronError("Failed to start cron", err).
 			WithMetadata("schedule", schedule)
@@ -236,6 +213,10 @@ func StartCron() error {
 
 	cronJob.Start()
 	isRunning = true
+
+	// Only an instance whose cron runs competes for leadership, so the
+	// leader is always an instance that sends
+	leader.Start()
 	logCronOperation("START", nil, 0, true, "Cron job started successfully")
 	log.Println("Cron job started")
 	return nil
@@ -253,6 +234,9 @@ func StopCron() {
 	cronJob.Remove(entryID)
 	isRunning = false
 
+	// Hand leadership to a replica whose cron is still running
+	leader.Stop()
+
 	description := fmt.Sprintf("Cron job stopped at %s", time.Now().Format(time.RFC3339))
 	logCronOperation("STOP", nil, 0, true, description)
 	log.Println(description)
@@ -0,0 +1,68 @@
+package cron
+
+import (
+	"fiber-app/pkg/config"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed deliveries are rescheduled
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Jitter is the fraction of the delay that is randomized, e.g. 0.2 = ±20%
+	Jitter float64
+}
+
+// retryPolicyFromEnv reads the retry policy from environment variables
+func retryPolicyFromEnv() RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    time.Hour,
+		Jitter:      0.2,
+	}
+
+	policy.MaxAttempts = config.Int("RETRY_MAX_ATTEMPTS", policy.MaxAttempts)
+	policy.BaseDelay = config.Duration("RETRY_BASE_DELAY", policy.BaseDelay)
+	policy.MaxDelay = config.Duration("RETRY_MAX_DELAY", policy.MaxDelay)
+	if value := os.Getenv("RETRY_JITTER"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 && parsed <= 1 {
+			policy.Jitter = parsed
+		} else {
+			log.Printf("Invalid RETRY_JITTER %q, using %.2f", value, policy.Jitter)
+		}
+	}
+
+	return policy
+}
+
+// Backoff returns the delay before the next attempt, given the number of
+// attempts already made. The delay doubles with each attempt, is capped at
+// MaxDelay and then randomized by Jitter.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// Exhausted reports whether no further attempts are allowed
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return attempts >= p.MaxAttempts
+}
@@ -0,0 +1,97 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    5 * time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{"zero attempts use the base delay", 0, 30 * time.Second},
+		{"negative attempts use the base delay", -3, 30 * time.Second},
+		{"first retry", 1, 30 * time.Second},
+		{"second retry doubles", 2, time.Minute},
+		{"third retry doubles again", 3, 2 * time.Minute},
+		{"fourth retry", 4, 4 * time.Minute},
+		{"capped at max delay", 5, 5 * time.Minute},
+		{"large attempt counts stay capped", 200, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Backoff(tt.attempts); got != tt.want {
+				t.Errorf("Backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		jitter   float64
+		center   time.Duration
+	}{
+		{"base delay", 1, 0.2, 10 * time.Second},
+		{"doubled delay", 3, 0.5, 40 * time.Second},
+		{"jitter applies after the cap", 10, 0.2, time.Minute},
+		{"full jitter", 2, 1, 20 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{
+				BaseDelay: 10 * time.Second,
+				MaxDelay:  time.Minute,
+				Jitter:    tt.jitter,
+			}
+			spread := time.Duration(float64(tt.center) * tt.jitter)
+			low, high := tt.center-spread, tt.center+spread
+
+			varied := false
+			for i := 0; i < 200; i++ {
+				got := policy.Backoff(tt.attempts)
+				if got < low || got > high {
+					t.Fatalf("Backoff(%d) = %s, want within [%s, %s]", tt.attempts, got, low, high)
+				}
+				if got != tt.center {
+					varied = true
+				}
+			}
+			if !varied {
+				t.Errorf("Backoff(%d) never deviated from %s", tt.attempts, tt.center)
+			}
+		})
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	tests := []struct {
+		attempts int
+		want     bool
+	}{
+		{0, false},
+		{2, false},
+		{3, true},
+		{4, true},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Exhausted(tt.attempts); got != tt.want {
+			t.Errorf("Exhausted(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
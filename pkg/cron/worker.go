@@ -0,0 +1,150 @@
+package cron
+
+import (
+	"context"
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type messageOutcome string
+
+const (
+	outcomeSent   messageOutcome = "sent"
+	outcomeRetry  messageOutcome = "retry"
+	outcomeFailed messageOutcome = "failed"
+	outcomeError  messageOutcome = "error"
+)
+
+// messageResult is the result of processing a single message in a run
+type messageResult struct {
+	MessageID uint
+	Outcome   messageOutcome
+	Err       error
+}
+
+// processMessages sends the messages using a bounded pool of workers and
+// returns one result per message
+func processMessages(messages []models.Message, workers int) []messageResult {
+	if workers > len(messages) {
+		workers = len(messages)
+	}
+
+	jobs := make(chan models.Message)
+	results := make(chan messageResult, len(messages))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for message := range jobs {
+				results <- processMessage(message)
+			}
+		}()
+	}
+
+	for _, message := range messages {
+		jobs <- message
+	}
+	close(jobs)
+
+	wg.Wait()
+	close(results)
+
+	collected := make([]messageResult, 0, len(messages))
+	for result := range results {
+		collected = append(collected, result)
+	}
+	return collected
+}
+
+// processMessage delivers a single message and records the outcome
+func processMessage(message models.Message) messageResult {
+	log.Printf("Processing message ID: %d", message.ID)
+
+	providerMessageID, err := messageProvider.Send(context.Background(), message)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			appErr.WithMetadata("messageId", message.ID).
+				WithMetadata("provider", messageProvider.Name())
+		}
+		errors.LogError(err)
+		return recordFailure(&message, err)
+	}
+
+	sentAt := time.Now()
+	message.MessageID = providerMessageID
+	message.Attempts++
+	message.LastError = ""
+	message.NextAttemptAt = nil
+	message.SentAt = &sentAt
+	err = message.UpdateStatus(claimed(&message), models.MessageStatusSent, map[string]interface{}{
+		"message_id":       message.MessageID,
+		"sent_at":          message.SentAt,
+		"attempts":         message.Attempts,
+		"last_error":       message.LastError,
+		"next_attempt_at":  nil,
+		"claim_token":      nil,
+		"lease_expires_at": nil,
+	})
+	if err != nil {
+		err = errors.NewDatabaseError("Error updating message status", err).
+			WithMetadata("messageId", message.ID).
+			WithMetadata("providerMessageId", providerMessageID)
+		errors.LogError(err)
+		return messageResult{MessageID: message.ID, Outcome: outcomeError, Err: err}
+	}
+
+	cacheMessage(message)
+
+	log.Printf("Successfully updated message %d with message_id %s", message.ID, providerMessageID)
+	return messageResult{MessageID: message.ID, Outcome: outcomeSent}
+}
+
+// cacheMessage writes the current state of a message to the cache
+func cacheMessage(message models.Message) {
+	message.UpdatedAt = time.Now()
+	if err := cache.SetMessageCache(message.ID, cache.NewMessageCache(message)); err != nil {
+		err = errors.NewCacheError("Error caching message", err).
+			WithMetadata("messageId", message.ID)
+		errors.LogError(err)
+	}
+}
+
+// uncacheMessages removes messages whose status changed in bulk
+func uncacheMessages(ids []uint) {
+	if err := cache.DeleteMessageCache(ids...); err != nil {
+		errors.LogError(errors.NewCacheError("Error removing cached messages", err))
+	}
+}
+
+// logRunResults aggregates the results of a run into a single cron log
+func logRunResults(results []messageResult) {
+	ids := make([]uint, len(results))
+	counts := make(map[messageOutcome]int)
+	var failures []string
+
+	for i, result := range results {
+		ids[i] = result.MessageID
+		counts[result.Outcome]++
+		if result.Err != nil {
+			failures = append(failures, fmt.Sprintf("%d: %v", result.MessageID, result.Err))
+		}
+	}
+
+	description := fmt.Sprintf("Sent: %d, Retrying: %d, Failed: %d, Errors: %d",
+		counts[outcomeSent], counts[outcomeRetry], counts[outcomeFailed], counts[outcomeError])
+	if len(failures) > 0 {
+		description += "\n" + strings.Join(failures, "\n")
+	}
+
+	success := counts[outcomeFailed] == 0 && counts[outcomeError] == 0 && counts[outcomeRetry] == 0
+	log.Printf("Run finished - %s", strings.SplitN(description, "\n", 2)[0])
+	logCronOperation("RUN", ids, len(results), success, description)
+}
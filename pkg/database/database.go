@@ -1,7 +1,10 @@
 package database
 
 import (
+	"errors"
 	"fiber-app/pkg/models"
+	"fiber-app/pkg/sms"
+	"fiber-app/pkg/templates"
 	"fmt"
 	"log"
 	"os"
@@ -54,37 +57,78 @@ func Connect() error {
 		return err
 	}
 
-	// Drop existing tables
-	if err := DB.Migrator().DropTable(&models.Message{}, &models.CronLog{}); err != nil {
-		log.Printf("Failed to drop tables: %v\n", err)
-		return err
-	}
-	log.Println("Existing tables dropped successfully")
+	log.Println("Database connection established successfully")
+	return nil
+}
 
-	// Create tables
-	if err := DB.AutoMigrate(&models.Message{}, &models.CronLog{}); err != nil {
-		log.Printf("Failed to create tables: %v\n", err)
-		return err
+// IsDuplicateKey reports whether err was caused by a row violating a unique
+// index
+func IsDuplicateKey(err error) bool {
+	if translator, ok := DB.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
 	}
-	log.Println("Tables created successfully")
-
-	// Insert default messages
-	defaultMessages := []models.Message{
-		{Content: "Hello! How can I help you?", Phone: "+905551234567", Status: false},
-		{Content: "Good day, your order is being prepared.", Phone: "+905551234568", Status: false},
-		{Content: "Your order has been shipped, it will arrive soon.", Phone: "+905551234569", Status: false},
-		{Content: "Would you like to be informed about our campaigns?", Phone: "+905551234570", Status: false},
-		{Content: "Update your profile for exclusive discount opportunities.", Phone: "+905551234571", Status: false},
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// seedTemplate is a demo template written in Turkish with an English variant
+type seedTemplate struct {
+	Name    string
+	Turkish string
+	English string
+}
+
+var seedTemplates = []seedTemplate{
+	{Name: "welcome", Turkish: "Merhaba! Size nasıl yardımcı olabilirim?", English: "Hello! How can I help you?"},
+	{Name: "order_preparing", Turkish: "İyi günler, siparişiniz hazırlanıyor.", English: "Good day, your order is being prepared."},
+	{Name: "order_shipped", Turkish: "Siparişiniz kargoya verildi, yakında elinizde olacak.", English: "Your order has been shipped, it will arrive soon."},
+	{Name: "campaign_opt_in", Turkish: "Kampanyalarımızdan haberdar olmak ister misiniz?", English: "Would you like to be informed about our campaigns?"},
+	{Name: "profile_discount", Turkish: "Size özel indirim fırsatları için profilinizi güncelleyin.", English: "Update your profile for exclusive discount opportunities."},
+}
+
+// Seed inserts the demo templates, each in tr-TR with an en-US variant, and
+// one demo message per template rendered in the default locale
+func Seed() (int, error) {
+	var defaultMessages []models.Message
+	for i, seed := range seedTemplates {
+		template := models.Template{Name: seed.Name}
+		err := DB.Where(template).
+			Attrs(models.Template{Locale: "tr-TR", Content: seed.Turkish}).
+			FirstOrCreate(&template).Error
+		if err != nil {
+			log.Printf("Failed to insert template %s: %v\n", seed.Name, err)
+			return 0, err
+		}
+
+		variant := models.TemplateVariant{TemplateID: template.ID, Locale: "en-US"}
+		err = DB.Where(variant).
+			Attrs(models.TemplateVariant{Content: seed.English}).
+			FirstOrCreate(&variant).Error
+		if err != nil {
+			log.Printf("Failed to insert template variant %s: %v\n", seed.Name, err)
+			return 0, err
+		}
+		template.Variants = []models.TemplateVariant{variant}
+
+		content, locale := templates.Localize(template, templates.DefaultLocale)
+		info := sms.Analyze(content)
+		defaultMessages = append(defaultMessages, models.Message{
+			Content:    content,
+			Encoding:   string(info.Encoding),
+			Segments:   info.Segments,
+			TemplateID: &template.ID,
+			Locale:     locale,
+			Phone:      fmt.Sprintf("+9055512345%02d", 67+i),
+			Status:     models.MessageStatusQueued,
+		})
 	}
 
 	if err := DB.Create(&defaultMessages).Error; err != nil {
 		log.Printf("Failed to insert default messages: %v\n", err)
-		return err
+		return 0, err
 	}
 	log.Printf("Successfully inserted %d default messages\n", len(defaultMessages))
 
-	log.Println("Database connection established and initialized successfully")
-	return nil
+	return len(defaultMessages), nil
 }
 
 // getEnvWithDefault returns environment variable value or default if not set
@@ -0,0 +1,55 @@
+package database
+
+import (
+	_ "embed"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// legacyMigrations are the versions whose schema the AutoMigrate tables of
+// earlier releases are converted to
+var legacyMigrations = []SchemaMigration{
+	{Version: 1, Name: "create_messages"},
+	{Version: 2, Name: "create_cron_logs"},
+}
+
+//go:embed legacy_schema.sql
+var legacySchemaScript string
+
+// upgradeLegacySchema converts a database created by AutoMigrate, before
+// versioned migrations existed, so the remaining migrations can be applied
+// to it. Those releases stored the message status as a boolean. The
+// converted tables are recorded as migrations 1 and 2. It reports whether
+// an upgrade was performed.
+func upgradeLegacySchema(db *gorm.DB, applied map[uint]SchemaMigration) (bool, error) {
+	if len(applied) > 0 {
+		return false, nil
+	}
+
+	var statusType string
+	err := db.Raw("SELECT DATA_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'messages' AND COLUMN_NAME = 'status'").
+		Scan(&statusType).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect messages table: %v", err)
+	}
+	if statusType != "tinyint" {
+		return false, nil
+	}
+
+	log.Println("Upgrading messages table created before versioned migrations")
+	if err := execScript(db, legacySchemaScript); err != nil {
+		return false, fmt.Errorf("legacy schema upgrade failed: %v", err)
+	}
+
+	for _, record := range legacyMigrations {
+		record.AppliedAt = time.Now()
+		if err := db.Create(&record).Error; err != nil {
+			return false, fmt.Errorf("failed to record migration %d_%s: %v", record.Version, record.Name, err)
+		}
+		applied[record.Version] = record
+	}
+	return true, nil
+}
@@ -0,0 +1,29 @@
+-- Converts the tables created by AutoMigrate before versioned migrations
+-- were introduced to the schema of migrations 000001 and 000002. The
+-- boolean status becomes sent (true) or queued (false).
+ALTER TABLE messages
+    ADD COLUMN status_v2 VARCHAR(20) NOT NULL DEFAULT 'queued' AFTER phone;
+
+UPDATE messages SET status_v2 = IF(status, 'sent', 'queued');
+
+ALTER TABLE messages
+    DROP COLUMN status,
+    CHANGE COLUMN status_v2 status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    MODIFY COLUMN message_id VARCHAR(100) NULL,
+    ADD COLUMN attempts BIGINT NOT NULL DEFAULT 0 AFTER message_id,
+    ADD COLUMN last_error TEXT NULL AFTER attempts,
+    ADD COLUMN next_attempt_at DATETIME(3) NULL AFTER last_error,
+    ADD COLUMN failed_at DATETIME(3) NULL AFTER next_attempt_at,
+    ADD INDEX idx_messages_status (status),
+    ADD INDEX idx_messages_next_attempt_at (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS cron_logs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    operation VARCHAR(50) NOT NULL,
+    message_ids TEXT NULL,
+    messages_count BIGINT NULL,
+    status TINYINT(1) NULL,
+    description TEXT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
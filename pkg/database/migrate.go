@@ -0,0 +1,311 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockTimeout is how long a run waits for another instance that is
+// migrating the same database
+const migrationLockTimeout = 5 * time.Minute
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is a versioned schema change with its up and down scripts
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState describes whether a migration has been applied
+type MigrationState struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+}
+
+// SchemaMigration is a row of the schema_migrations table
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// LoadMigrations reads the embedded migration scripts sorted by version.
+// Files are named <version>_<name>.up.sql and <version>_<name>.down.sql.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %v", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionPart, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name: %s", fileName)
+		}
+		version, err := strconv.ParseUint(versionPart, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %v", fileName, err)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", fileName, err)
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: name}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// MigrateUp applies all pending migrations in order and returns how many
+// were applied
+func MigrateUp() (int, error) {
+	count := 0
+	err := withMigrationLock(func(db *gorm.DB) error {
+		var err error
+		count, err = migrateUp(db)
+		return err
+	})
+	return count, err
+}
+
+// migrateUp applies the pending migrations on a connection holding the
+// migration lock
+func migrateUp(db *gorm.DB) (int, error) {
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	if upgraded, err := upgradeLegacySchema(db, applied); err != nil {
+		return 0, err
+	} else if upgraded {
+		count = len(legacyMigrations)
+	}
+
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		log.Printf("Applying migration %d_%s", migration.Version, migration.Name)
+		if err := execScript(db, migration.Up); err != nil {
+			return count, fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+		}
+
+		record := SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		if err := db.Create(&record).Error; err != nil {
+			return count, fmt.Errorf("failed to record migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// MigrateDown reverts the given number of most recently applied migrations
+func MigrateDown(steps int) (int, error) {
+	count := 0
+	err := withMigrationLock(func(db *gorm.DB) error {
+		var err error
+		count, err = migrateDown(db, steps)
+		return err
+	})
+	return count, err
+}
+
+// migrateDown reverts migrations on a connection holding the migration lock
+func migrateDown(db *gorm.DB, steps int) (int, error) {
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return count, fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+
+		log.Printf("Reverting migration %d_%s", migration.Version, migration.Name)
+		if err := execScript(db, migration.Down); err != nil {
+			return count, fmt.Errorf("reverting migration %d_%s failed: %v", migration.Version, migration.Name, err)
+		}
+
+		if err := db.Delete(&SchemaMigration{}, migration.Version).Error; err != nil {
+			return count, fmt.Errorf("failed to remove migration record %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// MigrationStatus lists every known migration with its applied time, if any
+func MigrationStatus() ([]MigrationState, error) {
+	migrations, applied, err := loadMigrationState(DB)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, len(migrations))
+	for i, migration := range migrations {
+		states[i] = MigrationState{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			states[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return states, nil
+}
+
+// withMigrationLock runs fn on a single connection holding a MySQL named
+// lock, so replicas starting together apply each migration only once. The
+// applied migrations must be read inside fn, after the lock is taken.
+func withMigrationLock(fn func(db *gorm.DB) error) error {
+	return DB.Connection(func(db *gorm.DB) error {
+		var acquired sql.NullInt64
+		err := db.Raw("SELECT GET_LOCK(CONCAT(DATABASE(), '.schema_migrations'), ?)", int(migrationLockTimeout.Seconds())).
+			Row().Scan(&acquired)
+		if err != nil {
+			return fmt.Errorf("failed to take the migration lock: %v", err)
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("timed out after %s waiting for another instance to finish migrating", migrationLockTimeout)
+		}
+		defer func() {
+			if err := db.Exec("SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.schema_migrations'))").Error; err != nil {
+				log.Printf("Failed to release the migration lock: %v", err)
+			}
+		}()
+
+		return fn(db)
+	})
+}
+
+// loadMigrationState ensures the schema_migrations table exists and returns
+// the known migrations together with the applied ones keyed by version
+func loadMigrationState(db *gorm.DB) ([]Migration, map[uint]SchemaMigration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, nil, fmt.Errorf("failed to create schema_migrations table: %v", err)
+	}
+
+	var records []SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+
+	applied := make(map[uint]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return migrations, applied, nil
+}
+
+// execScript runs each statement of a migration script in order
+func execScript(db *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements splits a script on semicolons that end a line. Comment
+// lines starting with "--" are dropped.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			statements = append(statements, statement)
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS messages (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    content VARCHAR(120) NOT NULL,
+    phone VARCHAR(15) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    message_id VARCHAR(100) NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    next_attempt_at DATETIME(3) NULL,
+    failed_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_messages_status (status),
+    INDEX idx_messages_next_attempt_at (next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
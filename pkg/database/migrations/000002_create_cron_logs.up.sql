@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS cron_logs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    operation VARCHAR(50) NOT NULL,
+    message_ids TEXT NULL,
+    messages_count BIGINT NULL,
+    status TINYINT(1) NULL,
+    description TEXT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+ALTER TABLE messages
+    DROP INDEX idx_messages_lease_expires_at,
+    DROP COLUMN lease_expires_at,
+    DROP COLUMN claim_token;
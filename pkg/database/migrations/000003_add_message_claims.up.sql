@@ -0,0 +1,4 @@
+ALTER TABLE messages
+    ADD COLUMN claim_token VARCHAR(64) NULL AFTER failed_at,
+    ADD COLUMN lease_expires_at DATETIME(3) NULL AFTER claim_token,
+    ADD INDEX idx_messages_lease_expires_at (lease_expires_at);
@@ -0,0 +1,3 @@
+ALTER TABLE messages
+    DROP INDEX idx_messages_send_at,
+    DROP COLUMN send_at;
@@ -0,0 +1,3 @@
+ALTER TABLE messages
+    ADD COLUMN send_at DATETIME(3) NULL AFTER last_error,
+    ADD INDEX idx_messages_send_at (send_at);
@@ -0,0 +1,3 @@
+ALTER TABLE messages
+    DROP INDEX idx_messages_expires_at,
+    DROP COLUMN expires_at;
@@ -0,0 +1,3 @@
+ALTER TABLE messages
+    ADD COLUMN expires_at DATETIME(3) NULL AFTER send_at,
+    ADD INDEX idx_messages_expires_at (expires_at);
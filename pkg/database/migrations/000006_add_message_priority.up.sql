@@ -0,0 +1,3 @@
+ALTER TABLE messages
+    ADD COLUMN priority TINYINT NOT NULL DEFAULT 5 AFTER status,
+    ADD INDEX idx_messages_status_priority (status, priority);
@@ -0,0 +1,5 @@
+ALTER TABLE messages
+    DROP INDEX idx_messages_phone,
+    DROP INDEX idx_messages_created_at,
+    DROP INDEX idx_messages_sent_at,
+    DROP COLUMN sent_at;
@@ -0,0 +1,7 @@
+ALTER TABLE messages
+    ADD COLUMN sent_at DATETIME(3) NULL AFTER next_attempt_at,
+    ADD INDEX idx_messages_sent_at (sent_at),
+    ADD INDEX idx_messages_created_at (created_at),
+    ADD INDEX idx_messages_phone (phone);
+
+UPDATE messages SET sent_at = updated_at WHERE status IN ('sent', 'delivered') AND sent_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE messages
+    ADD INDEX idx_messages_message_id (message_id);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    file_name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total_rows BIGINT NOT NULL DEFAULT 0,
+    processed_rows BIGINT NOT NULL DEFAULT 0,
+    created_count BIGINT NOT NULL DEFAULT 0,
+    failed_count BIGINT NOT NULL DEFAULT 0,
+    error TEXT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    completed_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS import_errors (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    import_job_id BIGINT UNSIGNED NOT NULL,
+    line_number BIGINT NOT NULL,
+    phone VARCHAR(50) NULL,
+    code VARCHAR(50) NOT NULL,
+    message VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_import_errors_import_job_id (import_job_id),
+    CONSTRAINT fk_import_errors_import_job FOREIGN KEY (import_job_id) REFERENCES import_jobs (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE messages MODIFY phone VARCHAR(15) NOT NULL;
@@ -0,0 +1,2 @@
+-- E.164 numbers are up to 15 digits plus the leading "+"
+ALTER TABLE messages MODIFY phone VARCHAR(16) NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE messages
+    DROP COLUMN segments,
+    DROP COLUMN encoding,
+    MODIFY content VARCHAR(120) NOT NULL;
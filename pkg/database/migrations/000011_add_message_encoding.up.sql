@@ -0,0 +1,5 @@
+-- Content may span several concatenated SMS segments
+ALTER TABLE messages
+    MODIFY content TEXT NOT NULL,
+    ADD COLUMN encoding VARCHAR(10) NOT NULL DEFAULT 'gsm7' AFTER content,
+    ADD COLUMN segments INT NOT NULL DEFAULT 1 AFTER encoding;
@@ -0,0 +1,6 @@
+ALTER TABLE messages
+    DROP FOREIGN KEY fk_messages_template,
+    DROP INDEX idx_messages_template_id,
+    DROP COLUMN template_id;
+
+DROP TABLE IF EXISTS templates;
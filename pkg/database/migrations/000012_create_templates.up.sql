@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS templates (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NULL,
+    content TEXT NOT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_templates_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE messages
+    ADD COLUMN template_id BIGINT UNSIGNED NULL AFTER segments,
+    ADD INDEX idx_messages_template_id (template_id),
+    ADD CONSTRAINT fk_messages_template FOREIGN KEY (template_id) REFERENCES templates (id) ON DELETE SET NULL;
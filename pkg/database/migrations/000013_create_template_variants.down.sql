@@ -0,0 +1,7 @@
+ALTER TABLE messages
+    DROP COLUMN locale;
+
+DROP TABLE IF EXISTS template_variants;
+
+ALTER TABLE templates
+    DROP COLUMN locale;
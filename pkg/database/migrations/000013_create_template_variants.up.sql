@@ -0,0 +1,18 @@
+-- An empty locale means the content is written in DEFAULT_LOCALE
+ALTER TABLE templates
+    ADD COLUMN locale VARCHAR(20) NOT NULL DEFAULT '' AFTER description;
+
+CREATE TABLE IF NOT EXISTS template_variants (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    template_id BIGINT UNSIGNED NOT NULL,
+    locale VARCHAR(20) NOT NULL,
+    content TEXT NOT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_template_variants_locale (template_id, locale),
+    CONSTRAINT fk_template_variants_template FOREIGN KEY (template_id) REFERENCES templates (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE messages
+    ADD COLUMN locale VARCHAR(20) NULL AFTER template_id;
@@ -0,0 +1,8 @@
+ALTER TABLE messages
+    DROP FOREIGN KEY fk_messages_contact,
+    DROP INDEX idx_messages_contact_id,
+    DROP COLUMN contact_id;
+
+DROP TABLE IF EXISTS group_contacts;
+DROP TABLE IF EXISTS `groups`;
+DROP TABLE IF EXISTS contacts;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS contacts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    phone VARCHAR(16) NOT NULL,
+    name VARCHAR(100) NULL,
+    locale VARCHAR(20) NULL,
+    timezone VARCHAR(64) NULL,
+    attributes JSON NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_contacts_phone (phone)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `groups` (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_groups_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS group_contacts (
+    group_id BIGINT UNSIGNED NOT NULL,
+    contact_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (group_id, contact_id),
+    INDEX idx_group_contacts_contact_id (contact_id),
+    CONSTRAINT fk_group_contacts_group FOREIGN KEY (group_id) REFERENCES `groups` (id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_contacts_contact FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE messages
+    ADD COLUMN contact_id BIGINT UNSIGNED NULL AFTER locale,
+    ADD INDEX idx_messages_contact_id (contact_id),
+    ADD CONSTRAINT fk_messages_contact FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE SET NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE messages
+    DROP FOREIGN KEY fk_messages_campaign,
+    DROP INDEX idx_messages_campaign_id_status,
+    DROP COLUMN campaign_id;
+
+DROP TABLE IF EXISTS campaign_recipients;
+DROP TABLE IF EXISTS campaigns;
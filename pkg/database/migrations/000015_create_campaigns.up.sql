@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    template_id BIGINT UNSIGNED NOT NULL,
+    locale VARCHAR(20) NULL,
+    variables JSON NULL,
+    audience VARCHAR(20) NOT NULL,
+    group_id BIGINT UNSIGNED NULL,
+    start_at DATETIME(3) NULL,
+    send_rate BIGINT NOT NULL DEFAULT 0,
+    skipped_count BIGINT NOT NULL DEFAULT 0,
+    started_at DATETIME(3) NULL,
+    paused_at DATETIME(3) NULL,
+    completed_at DATETIME(3) NULL,
+    cancelled_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_campaigns_status (status),
+    INDEX idx_campaigns_template_id (template_id),
+    INDEX idx_campaigns_group_id (group_id),
+    CONSTRAINT fk_campaigns_template FOREIGN KEY (template_id) REFERENCES templates (id),
+    CONSTRAINT fk_campaigns_group FOREIGN KEY (group_id) REFERENCES `groups` (id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS campaign_recipients (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    campaign_id BIGINT UNSIGNED NOT NULL,
+    phone VARCHAR(16) NOT NULL,
+    variables JSON NULL,
+    PRIMARY KEY (id),
+    INDEX idx_campaign_recipients_campaign_id (campaign_id),
+    CONSTRAINT fk_campaign_recipients_campaign FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE messages
+    ADD COLUMN campaign_id BIGINT UNSIGNED NULL AFTER contact_id,
+    ADD INDEX idx_messages_campaign_id_status (campaign_id, status),
+    ADD CONSTRAINT fk_messages_campaign FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE SET NULL;
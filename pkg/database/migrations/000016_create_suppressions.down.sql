@@ -0,0 +1,4 @@
+ALTER TABLE messages
+    DROP COLUMN category;
+
+DROP TABLE IF EXISTS suppressions;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS suppressions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    phone VARCHAR(16) NOT NULL,
+    scope VARCHAR(20) NOT NULL DEFAULT 'marketing',
+    reason VARCHAR(255) NULL,
+    source VARCHAR(50) NOT NULL DEFAULT 'api',
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_suppressions_phone_scope (phone, scope)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE messages
+    ADD COLUMN category VARCHAR(20) NOT NULL DEFAULT 'marketing' AFTER status;
@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs
+    DROP INDEX idx_import_jobs_status,
+    DROP COLUMN instance_id;
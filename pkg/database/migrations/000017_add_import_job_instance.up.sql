@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs
+    ADD COLUMN instance_id VARCHAR(100) NOT NULL DEFAULT '' AFTER status,
+    ADD INDEX idx_import_jobs_status (status);
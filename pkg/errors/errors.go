@@ -14,6 +14,8 @@ const (
 	ErrorTypeCache      ErrorType = "CACHE_ERROR"
 	ErrorTypeCron       ErrorType = "CRON_ERROR"
 	ErrorTypeValidation ErrorType = "VALIDATION_ERROR"
+	ErrorTypeNotFound   ErrorType = "NOT_FOUND"
+	ErrorTypeConflict   ErrorType = "CONFLICT"
 	ErrorTypeInternal   ErrorType = "INTERNAL_ERROR"
 )
 
@@ -92,3 +94,13 @@ func NewCacheError(message string, original error) *AppError {
 func NewCronError(message string, original error) *AppError {
 	return NewError(ErrorTypeCron, message, original)
 }
+
+// Not found errors
+func NewNotFoundError(message string, original error) *AppError {
+	return NewError(ErrorTypeNotFound, message, original)
+}
+
+// Conflict errors
+func NewConflictError(message string, original error) *AppError {
+	return NewError(ErrorTypeConflict, message, original)
+}
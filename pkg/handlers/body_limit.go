@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaxUploadSize is the largest request body the server accepts, sized for
+// CSV uploads
+const MaxUploadSize = 32 * 1024 * 1024
+
+// LimitBody holds every request to fiber's default body limit except POST
+// requests to the given upload paths, which may send up to MaxUploadSize.
+// The server is configured with MaxUploadSize because fiber only has a
+// server wide limit.
+func LimitBody(uploadPaths ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := fiber.DefaultBodyLimit
+		if c.Method() == fiber.MethodPost && isUploadPath(c.Path(), uploadPaths) {
+			limit = MaxUploadSize
+		}
+
+		size := c.Request().Header.ContentLength()
+		if size < 0 {
+			size = len(c.Body())
+		}
+		if size > limit {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+				Status:  "failed",
+				Message: "Request body is too large",
+				Code:    "BODY_TOO_LARGE",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// isUploadPath matches paths the way fiber routes them, ignoring case and a
+// trailing slash
+func isUploadPath(path string, uploadPaths []string) bool {
+	path = strings.TrimSuffix(path, "/")
+	for _, uploadPath := range uploadPaths {
+		if strings.EqualFold(path, uploadPath) {
+			return true
+		}
+	}
+	return false
+}
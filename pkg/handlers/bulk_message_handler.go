@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/config"
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const defaultBulkMaxMessages = 1000
+
+type BulkCreateMessageRequest struct {
+	Messages []CreateMessageRequest `json:"messages"`
+}
+
+type BulkMessageResult struct {
+	Index     int    `json:"index" example:"0"`
+	ContactID uint   `json:"contact_id,omitempty" example:"7"`
+	Status    string `json:"status" example:"created"`
+	ID        uint   `json:"id,omitempty" example:"42"`
+	Code      string `json:"code,omitempty" example:"INVALID_PHONE_FORMAT"`
+	Message   string `json:"message,omitempty" example:"Invalid phone number format"`
+}
+
+type BulkCreateMessageData struct {
+	Total   int                 `json:"total" example:"3"`
+	Created int                 `json:"created" example:"2"`
+	Failed  int                 `json:"failed" example:"1"`
+	Results []BulkMessageResult `json:"results"`
+}
+
+type BulkCreateMessageResponse struct {
+	Status string                `json:"status" example:"partial"`
+	Data   BulkCreateMessageData `json:"data"`
+}
+
+// @Summary Create messages in bulk
+// @Description Validates each message like POST /messages and inserts the valid ones in a single transaction. Returns a result per item
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param messages body BulkCreateMessageRequest true "Messages to create"
+// @Param Idempotency-Key header string false "Replays with the same key and body return the original response"
+// @Success 201 {object} BulkCreateMessageResponse "At least one message created"
+// @Failure 400 {object} BulkCreateMessageResponse "No valid messages"
+// @Failure 409 {object} ErrorResponse "Idempotency key reused with a different body or still in progress"
+// @Failure 413 {object} ErrorResponse "Too many messages"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/bulk [post]
+func CreateMessagesBulk(c *fiber.Ctx) error {
+	var request BulkCreateMessageRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	if len(request.Messages) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Messages field is required",
+			Code:    "MESSAGES_REQUIRED",
+		})
+	}
+
+	maxMessages := bulkMaxMessages()
+	if len(request.Messages) > maxMessages {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: fmt.Sprintf("A bulk request cannot contain more than %d messages", maxMessages),
+			Code:    "TOO_MANY_MESSAGES",
+		})
+	}
+
+	results := make([]BulkMessageResult, len(request.Messages))
+	var valid []*models.Message
+	var validIndexes []int
+	for i, item := range request.Messages {
+		message, validationErr := newMessageFromRequest(item)
+		if validationErr != nil {
+			results[i] = BulkMessageResult{
+				Index:   i,
+				Status:  "failed",
+				Code:    validationErr.Code,
+				Message: validationErr.Message,
+			}
+			continue
+		}
+		valid = append(valid, message)
+		validIndexes = append(validIndexes, i)
+	}
+
+	if err := insertMessages(valid); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create messages",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	for i, message := range valid {
+		results[validIndexes[i]] = BulkMessageResult{
+			Index:  validIndexes[i],
+			Status: "created",
+			ID:     message.ID,
+		}
+	}
+
+	data := BulkCreateMessageData{
+		Total:   len(request.Messages),
+		Created: len(valid),
+		Failed:  len(request.Messages) - len(valid),
+		Results: results,
+	}
+
+	return respondWithBulkResults(c, data)
+}
+
+// respondWithBulkResults writes per-item results. The request fails only if
+// no message could be created.
+func respondWithBulkResults(c *fiber.Ctx, data BulkCreateMessageData) error {
+	switch {
+	case data.Created == 0:
+		return c.Status(fiber.StatusBadRequest).JSON(BulkCreateMessageResponse{Status: "failed", Data: data})
+	case data.Failed > 0:
+		return c.Status(fiber.StatusCreated).JSON(BulkCreateMessageResponse{Status: "partial", Data: data})
+	default:
+		return c.Status(fiber.StatusCreated).JSON(BulkCreateMessageResponse{Status: "success", Data: data})
+	}
+}
+
+// insertMessages creates validated messages in a single transaction and
+// invalidates cached message lists
+func insertMessages(messages []*models.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(messages, 100).Error
+	})
+	if err != nil {
+		err = errors.NewDatabaseError("Error creating messages", err).
+			WithMetadata("count", len(messages))
+		errors.LogError(err)
+		return err
+	}
+
+	if err := cache.InvalidateMessageLists(); err != nil {
+		log.Printf("Cache invalidation error: %v", err)
+	}
+	return nil
+}
+
+// bulkMaxMessages returns the maximum number of items per bulk request
+func bulkMaxMessages() int {
+	return config.Int("BULK_MAX_MESSAGES", defaultBulkMaxMessages)
+}
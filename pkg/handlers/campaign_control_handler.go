@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// @Summary Start campaign
+// @Description Creates one message per recipient of a draft campaign. Messages are scheduled from start_at, or now, spaced out to respect the send rate. Recipients whose message cannot be rendered are skipped
+// @Tags campaigns
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} CampaignResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Campaign not found"
+// @Failure 409 {object} ErrorResponse "Campaign is no longer a draft"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns/{id}/start [post]
+func StartCampaign(c *fiber.Ctx) error {
+	campaign, err := findCampaign(c)
+	if campaign == nil {
+		return err
+	}
+	if campaign.Status != models.CampaignStatusDraft {
+		return c.Status(fiber.StatusConflict).JSON(campaignNotDraft)
+	}
+
+	template, loadErr := loadTemplate(campaign.TemplateID)
+	if loadErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(loadErr)
+	}
+
+	requests, err := campaignRequests(*campaign)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve campaign recipients",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if len(requests) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Campaign has no recipients",
+			Code:    "CAMPAIGN_NO_RECIPIENTS",
+		})
+	}
+
+	now := time.Now()
+	start := now
+	next := models.CampaignStatusRunning
+	if campaign.StartAt != nil && campaign.StartAt.After(now) {
+		start = *campaign.StartAt
+		next = models.CampaignStatusScheduled
+	}
+	interval := campaignSendInterval(campaign.SendRate)
+
+	var messages []*models.Message
+	var firstErr *ErrorResponse
+	for _, request := range requests {
+		request.template = template
+		message, validationErr := newMessageFromRequest(request)
+		if validationErr != nil {
+			if firstErr == nil {
+				firstErr = validationErr
+			}
+			continue
+		}
+
+		sendAt := start.Add(time.Duration(len(messages)) * interval)
+		message.SendAt = &sendAt
+		message.CampaignID = &campaign.ID
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: fmt.Sprintf("None of the %d recipients can be messaged: %s", len(requests), firstErr.Message),
+			Code:    "CAMPAIGN_NO_VALID_RECIPIENTS",
+		})
+	}
+
+	skipped := len(requests) - len(messages)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := campaign.UpdateStatus(tx, next, map[string]interface{}{
+			"started_at":    now,
+			"skipped_count": skipped,
+		}); err != nil {
+			return err
+		}
+		return tx.CreateInBatches(messages, 100).Error
+	})
+	if err != nil {
+		if errors.IsType(err, errors.ErrorTypeConflict) {
+			return c.Status(fiber.StatusConflict).JSON(campaignNotDraft)
+		}
+		errors.LogError(errors.NewDatabaseError("Error starting campaign", err).
+			WithMetadata("campaignId", campaign.ID))
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to start campaign",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	campaign.StartedAt = &now
+	campaign.SkippedCount = skipped
+	if err := cache.InvalidateMessageLists(); err != nil {
+		log.Printf("Cache invalidation error: %v", err)
+	}
+
+	log.Printf("Started campaign %d with %d messages, %d recipients skipped", campaign.ID, len(messages), skipped)
+
+	return respondWithCampaign(c, *campaign)
+}
+
+// @Summary Pause campaign
+// @Description Stops a scheduled or running campaign from sending further messages. Messages already being sent are not recalled
+// @Tags campaigns
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} CampaignResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Campaign not found"
+// @Failure 409 {object} ErrorResponse "Campaign cannot be paused"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns/{id}/pause [post]
+func PauseCampaign(c *fiber.Ctx) error {
+	campaign, err := findCampaign(c)
+	if campaign == nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := campaign.UpdateStatus(database.DB, models.CampaignStatusPaused, map[string]interface{}{
+		"paused_at": now,
+	}); err != nil {
+		return campaignStatusError(c, campaign, err)
+	}
+	campaign.PausedAt = &now
+
+	return respondWithCampaign(c, *campaign)
+}
+
+// @Summary Resume campaign
+// @Description Continues a paused campaign. Its remaining messages are pushed back by the time the campaign was paused, so the send rate is kept
+// @Tags campaigns
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} CampaignResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Campaign not found"
+// @Failure 409 {object} ErrorResponse "Campaign is not paused"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns/{id}/resume [post]
+func ResumeCampaign(c *fiber.Ctx) error {
+	campaign, err := findCampaign(c)
+	if campaign == nil {
+		return err
+	}
+
+	now := time.Now()
+	next := models.CampaignStatusRunning
+	if campaign.StartAt != nil && campaign.StartAt.After(now) {
+		next = models.CampaignStatusScheduled
+	}
+
+	var pausedFor time.Duration
+	if campaign.PausedAt != nil {
+		pausedFor = now.Sub(*campaign.PausedAt)
+	}
+
+	var ids []uint
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := campaign.UpdateStatus(tx, next, map[string]interface{}{"paused_at": nil}); err != nil {
+			return err
+		}
+
+		seconds := int64(pausedFor / time.Second)
+		if seconds <= 0 {
+			return nil
+		}
+		remaining := tx.Model(&models.Message{}).
+			Where("campaign_id = ? AND status = ?", campaign.ID, models.MessageStatusQueued)
+		if err := remaining.Session(&gorm.Session{}).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		return remaining.Session(&gorm.Session{}).
+			Update("send_at", gorm.Expr("DATE_ADD(send_at, INTERVAL ? SECOND)", seconds)).Error
+	})
+	if err != nil {
+		return campaignStatusError(c, campaign, err)
+	}
+	campaign.PausedAt = nil
+
+	if len(ids) > 0 {
+		if err := cache.DeleteMessageCache(ids...); err != nil {
+			log.Printf("Cache invalidation error: %v", err)
+		}
+		if err := cache.InvalidateMessageLists(); err != nil {
+			log.Printf("Cache invalidation error: %v", err)
+		}
+	}
+
+	return respondWithCampaign(c, *campaign)
+}
+
+// @Summary Cancel campaign
+// @Description Cancels a campaign and all of its messages that have not been sent yet
+// @Tags campaigns
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} CampaignResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Campaign not found"
+// @Failure 409 {object} ErrorResponse "Campaign has already finished"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns/{id}/cancel [post]
+func CancelCampaign(c *fiber.Ctx) error {
+	campaign, err := findCampaign(c)
+	if campaign == nil {
+		return err
+	}
+
+	now := time.Now()
+	var ids []uint
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := campaign.UpdateStatus(tx, models.CampaignStatusCancelled, map[string]interface{}{
+			"cancelled_at": now,
+			"paused_at":    nil,
+		}); err != nil {
+			return err
+		}
+
+		queued := tx.Model(&models.Message{}).
+			Where("campaign_id = ? AND status = ?", campaign.ID, models.MessageStatusQueued)
+		if err := queued.Session(&gorm.Session{}).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		_, err := models.TransitionMessages(tx, ids, models.MessageStatusQueued, models.MessageStatusCancelled,
+			map[string]interface{}{"next_attempt_at": nil})
+		return err
+	})
+	if err != nil {
+		return campaignStatusError(c, campaign, err)
+	}
+	campaign.CancelledAt = &now
+	campaign.PausedAt = nil
+
+	if len(ids) > 0 {
+		if err := cache.DeleteMessageCache(ids...); err != nil {
+			log.Printf("Cache invalidation error: %v", err)
+		}
+		if err := cache.InvalidateMessageLists(); err != nil {
+			log.Printf("Cache invalidation error: %v", err)
+		}
+	}
+
+	log.Printf("Cancelled campaign %d and %d queued messages", campaign.ID, len(ids))
+
+	return respondWithCampaign(c, *campaign)
+}
+
+// campaignStatusError writes the response for a failed campaign status change
+func campaignStatusError(c *fiber.Ctx, campaign *models.Campaign, err error) error {
+	if errors.IsType(err, errors.ErrorTypeConflict) {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: fmt.Sprintf("Campaign is %s and cannot be changed this way", campaign.Status),
+			Code:    "INVALID_CAMPAIGN_STATUS",
+		})
+	}
+
+	errors.LogError(errors.NewDatabaseError("Error updating campaign", err).
+		WithMetadata("campaignId", campaign.ID))
+	return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+		Status:  "failed",
+		Message: "Failed to update campaign",
+		Code:    "DATABASE_ERROR",
+	})
+}
+
+// campaignSendInterval is the delay between two messages of a campaign
+// sending rate messages per minute
+func campaignSendInterval(rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Minute / time.Duration(rate)
+}
+
+// campaignRequests builds one create request per recipient of a campaign.
+// Variables are layered like group messages: the recipient's phone and
+// name, then the campaign variables, then the recipient's own.
+func campaignRequests(campaign models.Campaign) ([]CreateMessageRequest, error) {
+	templateID := campaign.TemplateID
+	base := CreateMessageRequest{
+		TemplateID: &templateID,
+		Locale:     campaign.Locale,
+		Variables:  campaign.Variables,
+	}
+
+	var requests []CreateMessageRequest
+	if campaign.Audience == models.CampaignAudienceGroup {
+		if campaign.GroupID == nil {
+			return nil, nil
+		}
+		var contacts []models.Contact
+		if err := groupContacts(*campaign.GroupID).Order("contacts.id asc").Find(&contacts).Error; err != nil {
+			log.Printf("Error loading contacts of campaign %d: %v", campaign.ID, err)
+			return nil, err
+		}
+		for _, contact := range contacts {
+			requests = append(requests, personalizeRequest(base, contact))
+		}
+		return requests, nil
+	}
+
+	var recipients []models.CampaignRecipient
+	if err := database.DB.Where("campaign_id = ?", campaign.ID).Order("id asc").Find(&recipients).Error; err != nil {
+		log.Printf("Error loading recipients of campaign %d: %v", campaign.ID, err)
+		return nil, err
+	}
+	for _, recipient := range recipients {
+		variables := map[string]interface{}{"phone": recipient.Phone}
+		for name, value := range campaign.Variables {
+			variables[name] = value
+		}
+		for name, value := range recipient.Variables {
+			variables[name] = value
+		}
+
+		request := base
+		request.Phone = recipient.Phone
+		request.Variables = variables
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
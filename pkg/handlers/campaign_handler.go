@@ -0,0 +1,632 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/models"
+	"fiber-app/pkg/templates"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// CampaignRequest creates a campaign. The audience is a group, a list of
+// phones, or "csv" for recipients uploaded afterwards.
+type CampaignRequest struct {
+	Name       string                 `json:"name" example:"Spring sale"`
+	TemplateID uint                   `json:"template_id" example:"1"`
+	Locale     string                 `json:"locale,omitempty" example:"tr-TR"`
+	Variables  map[string]interface{} `json:"variables,omitempty" swaggertype:"object"`
+	Audience   string                 `json:"audience" example:"group" enums:"group,phones,csv"`
+	GroupID    *uint                  `json:"group_id,omitempty" example:"3"`
+	Phones     []string               `json:"phones,omitempty" example:"+905551234567,+905551234568"`
+	StartAt    string                 `json:"start_at,omitempty" example:"2025-03-01T09:30:00+03:00"`
+	// SendRate limits delivery to this many messages per minute, 0 for no limit
+	SendRate int `json:"send_rate,omitempty" example:"60"`
+}
+
+// CampaignProgress counts the messages of a campaign by status
+type CampaignProgress struct {
+	Total int64 `json:"total" example:"100"`
+	// Queued includes messages currently being sent
+	Queued int64 `json:"queued" example:"40"`
+	// Sent includes delivered messages
+	Sent      int64 `json:"sent" example:"55"`
+	Failed    int64 `json:"failed" example:"5"`
+	Cancelled int64 `json:"cancelled" example:"0"`
+	Expired   int64 `json:"expired" example:"0"`
+	// Suppressed messages were held back because the recipient opted out
+	Suppressed int64 `json:"suppressed" example:"0"`
+}
+
+// CampaignData is a campaign together with its progress
+type CampaignData struct {
+	models.Campaign
+	Progress CampaignProgress `json:"progress"`
+}
+
+type CampaignResponse struct {
+	Status string       `json:"status" example:"success"`
+	Data   CampaignData `json:"data"`
+}
+
+type CampaignListResponse struct {
+	Status     string         `json:"status" example:"success"`
+	Data       []CampaignData `json:"data"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// CampaignRecipientError describes a CSV row that was not added
+type CampaignRecipientError struct {
+	Line    int    `json:"line" example:"7"`
+	Code    string `json:"code" example:"INVALID_PHONE_FORMAT"`
+	Message string `json:"message" example:"Invalid phone number format"`
+}
+
+type CampaignRecipientsData struct {
+	Added  int                      `json:"added" example:"98"`
+	Failed int                      `json:"failed" example:"2"`
+	Errors []CampaignRecipientError `json:"errors"`
+}
+
+type CampaignRecipientsResponse struct {
+	Status string                 `json:"status" example:"success"`
+	Data   CampaignRecipientsData `json:"data"`
+}
+
+// @Summary Create campaign
+// @Description Creates a draft campaign sending a template to a group, a list of phones or recipients uploaded as CSV. Messages are created when the campaign is started
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param campaign body CampaignRequest true "Campaign"
+// @Success 201 {object} CampaignResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns [post]
+func CreateCampaign(c *fiber.Ctx) error {
+	var request CampaignRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	campaign := &models.Campaign{Status: models.CampaignStatusDraft}
+	recipients, validationErr := applyCampaignRequest(campaign, request)
+	if validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return err
+		}
+		for i := range recipients {
+			recipients[i].CampaignID = campaign.ID
+		}
+		if len(recipients) > 0 {
+			return tx.CreateInBatches(recipients, 100).Error
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error creating campaign: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create campaign",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CampaignResponse{
+		Status: "success",
+		Data:   CampaignData{Campaign: *campaign},
+	})
+}
+
+// @Summary List campaigns
+// @Description Retrieves a page of campaigns, newest first, with their progress
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param status query string false "Campaign status" Enums(draft, scheduled, running, paused, completed, cancelled)
+// @Success 200 {object} CampaignListResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns [get]
+func GetCampaigns(c *fiber.Ctx) error {
+	page, pageErr := parsePage(c)
+	if pageErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(pageErr)
+	}
+
+	query := database.DB.Model(&models.Campaign{})
+	if value := c.Query("status"); value != "" {
+		status := models.CampaignStatus(value)
+		if !status.IsValid() {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Status:  "failed",
+				Message: "Invalid campaign status",
+				Code:    "INVALID_STATUS",
+			})
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve campaigns",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	var campaigns []models.Campaign
+	err := query.Session(&gorm.Session{}).
+		Order("created_at desc, id desc").
+		Offset(page.offset()).Limit(page.Limit).
+		Find(&campaigns).Error
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve campaigns",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	data, err := withCampaignProgress(campaigns...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve campaign progress",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(CampaignListResponse{
+		Status:     "success",
+		Data:       data,
+		Pagination: page.withTotal(total),
+	})
+}
+
+// @Summary Get campaign
+// @Description Retrieves a campaign by ID with the number of its messages queued, sent, failed, cancelled, expired and suppressed
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} CampaignResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Campaign not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns/{id} [get]
+func GetCampaign(c *fiber.Ctx) error {
+	campaign, err := findCampaign(c)
+	if campaign == nil {
+		return err
+	}
+
+	return respondWithCampaign(c, *campaign)
+}
+
+// @Summary Upload campaign recipients
+// @Description Adds recipients to a draft campaign with a csv audience. The file needs a phone column; every other column becomes a template variable of its row
+// @Tags campaigns
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Param file formData file true "CSV file"
+// @Success 200 {object} CampaignRecipientsResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Campaign not found"
+// @Failure 409 {object} ErrorResponse "Campaign is no longer a draft"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /campaigns/{id}/recipients [post]
+func UploadCampaignRecipients(c *fiber.Ctx) error {
+	campaign, err := findCampaign(c)
+	if campaign == nil {
+		return err
+	}
+
+	if campaign.Audience != models.CampaignAudienceCSV {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Recipients can only be uploaded to campaigns with a csv audience",
+			Code:    "INVALID_AUDIENCE",
+		})
+	}
+	if campaign.Status != models.CampaignStatusDraft {
+		return c.Status(fiber.StatusConflict).JSON(campaignNotDraft)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "CSV file is required in the file field",
+			Code:    "FILE_REQUIRED",
+		})
+	}
+	if !strings.EqualFold(filepath.Ext(fileHeader.Filename), ".csv") {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Only .csv files can be uploaded",
+			Code:    "INVALID_FILE_TYPE",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("Error opening uploaded file: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to read uploaded file",
+			Code:    "UPLOAD_ERROR",
+		})
+	}
+	defer file.Close()
+
+	recipients, data, parseErr := parseCampaignRecipients(file, campaign.ID)
+	if parseErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(parseErr)
+	}
+
+	if len(recipients) > 0 {
+		if err := database.DB.CreateInBatches(recipients, 100).Error; err != nil {
+			log.Printf("Error adding campaign recipients: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+				Status:  "failed",
+				Message: "Failed to add recipients",
+				Code:    "DATABASE_ERROR",
+			})
+		}
+	}
+
+	log.Printf("Added %d recipients to campaign %d, %d rows rejected", data.Added, campaign.ID, data.Failed)
+
+	return c.JSON(CampaignRecipientsResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+var campaignNotDraft = ErrorResponse{
+	Status:  "failed",
+	Message: "Campaign has already been started",
+	Code:    "CAMPAIGN_NOT_DRAFT",
+}
+
+// applyCampaignRequest validates a request and copies it onto campaign. For
+// a phones audience it returns the recipients to store.
+func applyCampaignRequest(campaign *models.Campaign, request CampaignRequest) ([]*models.CampaignRecipient, *ErrorResponse) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, &ErrorResponse{
+			Status:  "failed",
+			Message: "Name field is required",
+			Code:    "NAME_REQUIRED",
+		}
+	}
+	if len(name) > 100 {
+		return nil, &ErrorResponse{
+			Status:  "failed",
+			Message: "Name cannot exceed 100 characters",
+			Code:    "NAME_TOO_LONG",
+		}
+	}
+
+	if request.TemplateID == 0 {
+		return nil, &ErrorResponse{
+			Status:  "failed",
+			Message: "template_id field is required",
+			Code:    "TEMPLATE_REQUIRED",
+		}
+	}
+	if _, loadErr := loadTemplate(request.TemplateID); loadErr != nil {
+		return nil, loadErr
+	}
+
+	locale := ""
+	if request.Locale != "" {
+		normalized, err := templates.NormalizeLocale(request.Locale)
+		if err != nil {
+			return nil, invalidLocale(err)
+		}
+		locale = normalized
+	}
+
+	var startAt *time.Time
+	if request.StartAt != "" {
+		parsed, err := time.Parse(time.RFC3339, request.StartAt)
+		if err != nil {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Invalid start_at format. Use RFC3339 with timezone, e.g. 2025-03-01T09:30:00+03:00",
+				Code:    "INVALID_START_AT",
+			}
+		}
+		startAt = &parsed
+	}
+
+	if request.SendRate < 0 {
+		return nil, &ErrorResponse{
+			Status:  "failed",
+			Message: "send_rate cannot be negative",
+			Code:    "INVALID_SEND_RATE",
+		}
+	}
+
+	audience := models.CampaignAudience(request.Audience)
+	var recipients []*models.CampaignRecipient
+	switch audience {
+	case models.CampaignAudienceGroup:
+		if request.GroupID == nil || len(request.Phones) > 0 {
+			return nil, invalidAudience("A group audience needs group_id and no phones")
+		}
+		var count int64
+		if err := database.DB.Model(&models.Group{}).Where("id = ?", *request.GroupID).Count(&count).Error; err != nil {
+			log.Printf("Error loading group %d: %v", *request.GroupID, err)
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Failed to retrieve group",
+				Code:    "DATABASE_ERROR",
+			}
+		} else if count == 0 {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Group not found",
+				Code:    "GROUP_NOT_FOUND",
+			}
+		}
+	case models.CampaignAudiencePhones:
+		if request.GroupID != nil || len(request.Phones) == 0 {
+			return nil, invalidAudience("A phones audience needs phones and no group_id")
+		}
+		if maxPhones := bulkMaxMessages(); len(request.Phones) > maxPhones {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: fmt.Sprintf("A campaign can list at most %d phones, upload larger audiences as CSV", maxPhones),
+				Code:    "TOO_MANY_PHONES",
+			}
+		}
+		seen := make(map[string]bool, len(request.Phones))
+		for i, raw := range request.Phones {
+			phoneNumber, phoneErr := normalizePhone(raw)
+			if phoneErr != nil {
+				phoneErr.Message = fmt.Sprintf("phones[%d]: %s", i, phoneErr.Message)
+				return nil, phoneErr
+			}
+			if seen[phoneNumber] {
+				continue
+			}
+			seen[phoneNumber] = true
+			recipients = append(recipients, &models.CampaignRecipient{Phone: phoneNumber})
+		}
+	case models.CampaignAudienceCSV:
+		if request.GroupID != nil || len(request.Phones) > 0 {
+			return nil, invalidAudience("A csv audience takes its recipients from an uploaded file")
+		}
+	default:
+		return nil, invalidAudience("audience must be one of group, phones or csv")
+	}
+
+	campaign.Name = name
+	campaign.TemplateID = request.TemplateID
+	campaign.Locale = locale
+	campaign.Variables = models.Attributes(request.Variables)
+	if campaign.Variables == nil {
+		campaign.Variables = models.Attributes{}
+	}
+	campaign.Audience = audience
+	campaign.GroupID = request.GroupID
+	campaign.StartAt = startAt
+	campaign.SendRate = request.SendRate
+	return recipients, nil
+}
+
+func invalidAudience(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Status:  "failed",
+		Message: message,
+		Code:    "INVALID_AUDIENCE",
+	}
+}
+
+// parseCampaignRecipients reads an uploaded recipient list. Rows with an
+// invalid phone number or duplicating an earlier row are reported and
+// skipped.
+func parseCampaignRecipients(file io.Reader, campaignID uint) ([]*models.CampaignRecipient, CampaignRecipientsData, *ErrorResponse) {
+	data := CampaignRecipientsData{Errors: []CampaignRecipientError{}}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		message := "File is empty"
+		if err != io.EOF {
+			message = "Invalid CSV header: " + err.Error()
+		}
+		return nil, data, &ErrorResponse{
+			Status:  "failed",
+			Message: message,
+			Code:    "INVALID_CSV",
+		}
+	}
+
+	phoneColumn := -1
+	for i, column := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if strings.EqualFold(header[i], "phone") {
+			phoneColumn = i
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, data, &ErrorResponse{
+			Status:  "failed",
+			Message: "CSV header must contain a phone column",
+			Code:    "INVALID_CSV",
+		}
+	}
+
+	var recipients []*models.CampaignRecipient
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			parseErr, ok := err.(*csv.ParseError)
+			if !ok {
+				return nil, data, &ErrorResponse{
+					Status:  "failed",
+					Message: "Failed to read CSV file",
+					Code:    "INVALID_CSV",
+				}
+			}
+			data.Errors = append(data.Errors, CampaignRecipientError{
+				Line:    parseErr.StartLine,
+				Code:    "INVALID_CSV_ROW",
+				Message: parseErr.Err.Error(),
+			})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		if phoneColumn >= len(record) || strings.TrimSpace(record[phoneColumn]) == "" {
+			data.Errors = append(data.Errors, CampaignRecipientError{
+				Line:    line,
+				Code:    "PHONE_REQUIRED",
+				Message: "Phone field is required",
+			})
+			continue
+		}
+
+		phoneNumber, phoneErr := normalizePhone(record[phoneColumn])
+		if phoneErr != nil {
+			data.Errors = append(data.Errors, CampaignRecipientError{
+				Line:    line,
+				Code:    phoneErr.Code,
+				Message: phoneErr.Message,
+			})
+			continue
+		}
+		if seen[phoneNumber] {
+			data.Errors = append(data.Errors, CampaignRecipientError{
+				Line:    line,
+				Code:    "DUPLICATE_PHONE",
+				Message: "Phone number already appears earlier in the file",
+			})
+			continue
+		}
+		seen[phoneNumber] = true
+
+		variables := models.Attributes{}
+		for i, value := range record {
+			if i != phoneColumn && i < len(header) && header[i] != "" {
+				variables[header[i]] = value
+			}
+		}
+		recipients = append(recipients, &models.CampaignRecipient{
+			CampaignID: campaignID,
+			Phone:      phoneNumber,
+			Variables:  variables,
+		})
+	}
+
+	data.Added = len(recipients)
+	data.Failed = len(data.Errors)
+	return recipients, data, nil
+}
+
+// withCampaignProgress derives the progress of campaigns from the status
+// of their messages
+func withCampaignProgress(campaigns ...models.Campaign) ([]CampaignData, error) {
+	data := make([]CampaignData, len(campaigns))
+	if len(campaigns) == 0 {
+		return data, nil
+	}
+
+	ids := make([]uint, len(campaigns))
+	index := make(map[uint]int, len(campaigns))
+	for i, campaign := range campaigns {
+		data[i].Campaign = campaign
+		ids[i] = campaign.ID
+		index[campaign.ID] = i
+	}
+
+	var counts []struct {
+		CampaignID uint
+		Status     models.MessageStatus
+		Count      int64
+	}
+	err := database.DB.Model(&models.Message{}).
+		Select("campaign_id, status, COUNT(*) AS count").
+		Where("campaign_id IN ?", ids).
+		Group("campaign_id, status").
+		Scan(&counts).Error
+	if err != nil {
+		log.Printf("Error counting campaign messages: %v", err)
+		return nil, err
+	}
+
+	for _, count := range counts {
+		progress := &data[index[count.CampaignID]].Progress
+		progress.Total += count.Count
+		switch count.Status {
+		case models.MessageStatusQueued, models.MessageStatusSending:
+			progress.Queued += count.Count
+		case models.MessageStatusSent, models.MessageStatusDelivered:
+			progress.Sent += count.Count
+		case models.MessageStatusFailed:
+			progress.Failed += count.Count
+		case models.MessageStatusCancelled:
+			progress.Cancelled += count.Count
+		case models.MessageStatusExpired:
+			progress.Expired += count.Count
+		case models.MessageStatusSuppressed:
+			progress.Suppressed += count.Count
+		}
+	}
+
+	return data, nil
+}
+
+// respondWithCampaign writes campaign together with its progress
+func respondWithCampaign(c *fiber.Ctx, campaign models.Campaign) error {
+	data, err := withCampaignProgress(campaign)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve campaign progress",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(CampaignResponse{
+		Status: "success",
+		Data:   data[0],
+	})
+}
+
+// findCampaign loads the campaign named by the id path parameter
+func findCampaign(c *fiber.Ctx) (*models.Campaign, error) {
+	return findRecord[models.Campaign](c, database.DB, "campaign", "CAMPAIGN")
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/models"
+	"fiber-app/pkg/phone"
+	"fiber-app/pkg/templates"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ContactRequest creates or replaces a contact. Attributes are available as
+// template variables when messaging the contact through a group.
+type ContactRequest struct {
+	Phone      string                 `json:"phone" example:"+905551234567"`
+	Name       string                 `json:"name,omitempty" example:"Ayşe Yılmaz"`
+	Locale     string                 `json:"locale,omitempty" example:"tr-TR"`
+	Timezone   string                 `json:"timezone,omitempty" example:"Europe/Istanbul"`
+	Attributes map[string]interface{} `json:"attributes,omitempty" swaggertype:"object"`
+}
+
+type ContactResponse struct {
+	Status string         `json:"status" example:"success"`
+	Data   models.Contact `json:"data"`
+}
+
+type ContactListResponse struct {
+	Status     string           `json:"status" example:"success"`
+	Data       []models.Contact `json:"data"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+// @Summary Create contact
+// @Description Creates a contact. The phone number is stored in E.164 form and must be unique
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param contact body ContactRequest true "Contact"
+// @Success 201 {object} ContactResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 409 {object} ErrorResponse "A contact with this phone already exists"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /contacts [post]
+func CreateContact(c *fiber.Ctx) error {
+	var request ContactRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	contact := &models.Contact{}
+	if validationErr := applyContactRequest(contact, request); validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	if taken, err := contactPhoneTaken(contact.Phone, 0); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create contact",
+			Code:    "DATABASE_ERROR",
+		})
+	} else if taken {
+		return c.Status(fiber.StatusConflict).JSON(contactPhoneConflict)
+	}
+
+	if err := database.DB.Create(contact).Error; err != nil {
+		log.Printf("Error creating contact: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create contact",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ContactResponse{
+		Status: "success",
+		Data:   *contact,
+	})
+}
+
+// @Summary List contacts
+// @Description Retrieves a page of contacts ordered by name
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param phone query string false "Phone number"
+// @Success 200 {object} ContactListResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /contacts [get]
+func GetContacts(c *fiber.Ctx) error {
+	query := database.DB.Model(&models.Contact{})
+	if value := strings.TrimSpace(c.Query("phone")); value != "" {
+		if normalized, err := phone.Normalize(value); err == nil {
+			value = normalized
+		}
+		query = query.Where("phone = ?", value)
+	}
+
+	return respondWithContacts(c, query)
+}
+
+// @Summary Get contact
+// @Description Retrieves a contact by ID
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Success 200 {object} ContactResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Contact not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /contacts/{id} [get]
+func GetContact(c *fiber.Ctx) error {
+	contact, err := findContact(c)
+	if contact == nil {
+		return err
+	}
+
+	return c.JSON(ContactResponse{
+		Status: "success",
+		Data:   *contact,
+	})
+}
+
+// @Summary Update contact
+// @Description Replaces the phone, name, locale, timezone and attributes of a contact
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param contact body ContactRequest true "Contact"
+// @Success 200 {object} ContactResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Contact not found"
+// @Failure 409 {object} ErrorResponse "A contact with this phone already exists"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /contacts/{id} [put]
+func UpdateContact(c *fiber.Ctx) error {
+	contact, err := findContact(c)
+	if contact == nil {
+		return err
+	}
+
+	var request ContactRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	if validationErr := applyContactRequest(contact, request); validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	if taken, err := contactPhoneTaken(contact.Phone, contact.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to update contact",
+			Code:    "DATABASE_ERROR",
+		})
+	} else if taken {
+		return c.Status(fiber.StatusConflict).JSON(contactPhoneConflict)
+	}
+
+	if err := database.DB.Save(contact).Error; err != nil {
+		log.Printf("Error updating contact: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to update contact",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(ContactResponse{
+		Status: "success",
+		Data:   *contact,
+	})
+}
+
+// @Summary Delete contact
+// @Description Deletes a contact and removes it from all groups. Messages sent to it are kept
+// @Tags contacts
+// @Param id path int true "Contact ID"
+// @Success 204 "Contact deleted"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Contact not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /contacts/{id} [delete]
+func DeleteContact(c *fiber.Ctx) error {
+	contact, err := findContact(c)
+	if contact == nil {
+		return err
+	}
+
+	if err := database.DB.Delete(contact).Error; err != nil {
+		log.Printf("Error deleting contact: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to delete contact",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+var contactPhoneConflict = ErrorResponse{
+	Status:  "failed",
+	Message: "A contact with this phone number already exists",
+	Code:    "CONTACT_EXISTS",
+}
+
+// applyContactRequest validates a request and copies it onto contact
+func applyContactRequest(contact *models.Contact, request ContactRequest) *ErrorResponse {
+	if request.Phone == "" {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Phone field is required",
+			Code:    "PHONE_REQUIRED",
+		}
+	}
+	phoneNumber, phoneErr := normalizePhone(request.Phone)
+	if phoneErr != nil {
+		return phoneErr
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if len(name) > 100 {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Name cannot exceed 100 characters",
+			Code:    "NAME_TOO_LONG",
+		}
+	}
+
+	locale := ""
+	if request.Locale != "" {
+		normalized, err := templates.NormalizeLocale(request.Locale)
+		if err != nil {
+			return invalidLocale(err)
+		}
+		locale = normalized
+	}
+
+	if request.Timezone != "" {
+		if _, err := time.LoadLocation(request.Timezone); err != nil {
+			return &ErrorResponse{
+				Status:  "failed",
+				Message: "Unknown timezone, use an IANA name such as Europe/Istanbul",
+				Code:    "INVALID_TIMEZONE",
+			}
+		}
+	}
+
+	contact.Phone = phoneNumber
+	contact.Name = name
+	contact.Locale = locale
+	contact.Timezone = request.Timezone
+	contact.Attributes = models.Attributes(request.Attributes)
+	if contact.Attributes == nil {
+		contact.Attributes = models.Attributes{}
+	}
+	return nil
+}
+
+// contactPhoneTaken reports whether another contact already uses phone
+func contactPhoneTaken(phoneNumber string, exceptID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.Contact{}).
+		Where("phone = ? AND id <> ?", phoneNumber, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// respondWithContacts writes one page of the contacts matched by query
+func respondWithContacts(c *fiber.Ctx, query *gorm.DB) error {
+	page, pageErr := parsePage(c)
+	if pageErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(pageErr)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve contacts",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	contacts := []models.Contact{}
+	err := query.Session(&gorm.Session{}).
+		Order("contacts.name asc, contacts.id asc").
+		Offset(page.offset()).Limit(page.Limit).
+		Find(&contacts).Error
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve contacts",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(ContactListResponse{
+		Status:     "success",
+		Data:       contacts,
+		Pagination: page.withTotal(total),
+	})
+}
+
+// findContact loads the contact named by the id path parameter
+func findContact(c *fiber.Ctx) (*models.Contact, error) {
+	return findRecord[models.Contact](c, database.DB, "contact", "CONTACT")
+}
@@ -2,14 +2,25 @@ package handlers
 
 import (
 	"fiber-app/pkg/cron"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/leader"
 	"fiber-app/pkg/models"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type CronStatusResponse struct {
-	Status    string `json:"status" example:"success"`
-	IsRunning bool   `json:"is_running" example:"true"`
+	Status     string `json:"status" example:"success"`
+	IsRunning  bool   `json:"is_running" example:"true"`
+	InstanceID string `json:"instance_id" example:"api-1-42"`
+	IsLeader   bool   `json:"is_leader" example:"true"`
+	// IsSending is true when this instance's cron runs and it holds
+	// leadership, only such an instance sends messages
+	IsSending bool   `json:"is_sending" example:"true"`
+	Leader    string `json:"leader" example:"api-1-42"`
+	// ElectionError is set while leader election cannot reach Redis. No
+	// instance sends messages until it recovers.
+	ElectionError string `json:"election_error,omitempty" example:"dial tcp 10.0.0.5:6379: connect: connection refused"`
 }
 
 type CronMessageResponse struct {
@@ -23,7 +34,7 @@ type CronLogsResponse struct {
 }
 
 // @Summary Start cron job
-// @Description Starts the message sending cron job
+// @Description Starts the message sending cron job of this instance, which then competes for leadership
 // @Tags cron
 // @Accept json
 // @Produce json
@@ -46,7 +57,7 @@ func StartCronJob(c *fiber.Ctx) error {
 }
 
 // @Summary Stop cron job
-// @Description Stops the message sending cron job
+// @Description Stops the message sending cron job of this instance. A leader releases leadership, so replicas with a running cron take over sending
 // @Tags cron
 // @Accept json
 // @Produce json
@@ -61,17 +72,32 @@ func StopCronJob(c *fiber.Ctx) error {
 }
 
 // @Summary Get cron job status
-// @Description Checks if the cron job is running
+// @Description Checks if the cron job of this instance is running and which instance is the scheduling leader. Only instances with a running cron compete for leadership, and is_sending is true on the one instance that sends. election_error reports that leader election cannot reach Redis, in which case no instance sends messages
 // @Tags cron
 // @Accept json
 // @Produce json
 // @Success 200 {object} CronStatusResponse "Successful response"
 // @Router /cron/status [get]
 func GetCronStatus(c *fiber.Ctx) error {
-	return c.JSON(CronStatusResponse{
-		Status:    "success",
-		IsRunning: cron.IsCronRunning(),
-	})
+	currentLeader, err := leader.CurrentLeader()
+	if err != nil {
+		errors.LogError(err)
+	}
+
+	isRunning := cron.IsCronRunning()
+	isLeader := leader.IsLeader()
+	response := CronStatusResponse{
+		Status:     "success",
+		IsRunning:  isRunning,
+		InstanceID: leader.InstanceID(),
+		IsLeader:   isLeader,
+		IsSending:  isRunning && isLeader,
+		Leader:     currentLeader,
+	}
+	if electionErr := leader.LastError(); electionErr != nil {
+		response.ElectionError = electionErr.Error()
+	}
+	return c.JSON(response)
 }
 
 // @Summary Get cron logs
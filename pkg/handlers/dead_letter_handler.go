@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fiber-app/pkg/cron"
+	"fiber-app/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Get dead messages
+// @Description Retrieves messages that failed permanently after exhausting their delivery attempts
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param limit query int false "Maximum number of messages" default(100)
+// @Success 200 {object} MessagesResponse "Successful response"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/dead [get]
+func GetDeadMessages(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	messages, err := cron.GetDeadMessages(limit)
+	if err != nil {
+		errors.LogError(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve dead messages",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(MessagesResponse{
+		Status: "success",
+		Data:   messages,
+	})
+}
+
+// @Summary Requeue failed message
+// @Description Moves a permanently failed message back into the sending queue
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Success 200 {object} MessageResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Message not found"
+// @Failure 409 {object} ErrorResponse "Message is not failed"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/{id}/requeue [post]
+func RequeueMessage(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid message ID",
+			Code:    "INVALID_MESSAGE_ID",
+		})
+	}
+
+	message, err := cron.RequeueMessage(uint(id))
+	if err != nil {
+		switch {
+		case errors.IsType(err, errors.ErrorTypeNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Status:  "failed",
+				Message: "Message not found",
+				Code:    "MESSAGE_NOT_FOUND",
+			})
+		case errors.IsType(err, errors.ErrorTypeConflict):
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+				Status:  "failed",
+				Message: "Only failed messages can be requeued",
+				Code:    "MESSAGE_NOT_FAILED",
+			})
+		}
+		errors.LogError(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to requeue message",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(MessageResponse{
+		Status: "success",
+		Data:   *message,
+	})
+}
@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GroupRequest struct {
+	Name        string `json:"name" example:"vip_customers"`
+	Description string `json:"description,omitempty" example:"Customers with more than ten orders"`
+}
+
+type GroupMembersRequest struct {
+	ContactIDs []uint `json:"contact_ids" example:"1,2,3"`
+}
+
+// GroupData is a group together with its number of members
+type GroupData struct {
+	models.Group
+	ContactCount int64 `json:"contact_count" example:"42"`
+}
+
+type GroupResponse struct {
+	Status string    `json:"status" example:"success"`
+	Data   GroupData `json:"data"`
+}
+
+type GroupsResponse struct {
+	Status string      `json:"status" example:"success"`
+	Data   []GroupData `json:"data"`
+}
+
+// @Summary Create group
+// @Description Creates a named group of contacts
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param group body GroupRequest true "Group"
+// @Success 201 {object} GroupResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 409 {object} ErrorResponse "Group name already in use"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups [post]
+func CreateGroup(c *fiber.Ctx) error {
+	var request GroupRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	group := &models.Group{}
+	if validationErr := applyGroupRequest(group, request); validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	if taken, err := groupNameTaken(group.Name, 0); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create group",
+			Code:    "DATABASE_ERROR",
+		})
+	} else if taken {
+		return c.Status(fiber.StatusConflict).JSON(groupNameConflict)
+	}
+
+	if err := database.DB.Omit("Contacts").Create(group).Error; err != nil {
+		log.Printf("Error creating group: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create group",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(GroupResponse{
+		Status: "success",
+		Data:   GroupData{Group: *group},
+	})
+}
+
+// @Summary List groups
+// @Description Retrieves all groups ordered by name with their number of members
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Success 200 {object} GroupsResponse "Successful response"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups [get]
+func GetGroups(c *fiber.Ctx) error {
+	groups := []GroupData{}
+	err := database.DB.Model(&models.Group{}).
+		Select("`groups`.*, (SELECT COUNT(*) FROM group_contacts WHERE group_contacts.group_id = `groups`.id) AS contact_count").
+		Order("name asc").
+		Scan(&groups).Error
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve groups",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(GroupsResponse{
+		Status: "success",
+		Data:   groups,
+	})
+}
+
+// @Summary Get group
+// @Description Retrieves a group and its number of members
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} GroupResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Group not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups/{id} [get]
+func GetGroup(c *fiber.Ctx) error {
+	group, err := findGroup(c)
+	if group == nil {
+		return err
+	}
+
+	return respondWithGroup(c, *group)
+}
+
+// @Summary Update group
+// @Description Changes the name and description of a group
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param group body GroupRequest true "Group"
+// @Success 200 {object} GroupResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Group not found"
+// @Failure 409 {object} ErrorResponse "Group name already in use"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups/{id} [put]
+func UpdateGroup(c *fiber.Ctx) error {
+	group, err := findGroup(c)
+	if group == nil {
+		return err
+	}
+
+	var request GroupRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	if validationErr := applyGroupRequest(group, request); validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	if taken, err := groupNameTaken(group.Name, group.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to update group",
+			Code:    "DATABASE_ERROR",
+		})
+	} else if taken {
+		return c.Status(fiber.StatusConflict).JSON(groupNameConflict)
+	}
+
+	if err := database.DB.Omit("Contacts").Save(group).Error; err != nil {
+		log.Printf("Error updating group: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to update group",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return respondWithGroup(c, *group)
+}
+
+// @Summary Delete group
+// @Description Deletes a group. Its contacts are kept
+// @Tags groups
+// @Param id path int true "Group ID"
+// @Success 204 "Group deleted"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Group not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups/{id} [delete]
+func DeleteGroup(c *fiber.Ctx) error {
+	group, err := findGroup(c)
+	if group == nil {
+		return err
+	}
+
+	if err := database.DB.Delete(group).Error; err != nil {
+		log.Printf("Error deleting group: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to delete group",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary List group members
+// @Description Retrieves a page of the contacts in a group
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} ContactListResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Group not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups/{id}/contacts [get]
+func GetGroupContacts(c *fiber.Ctx) error {
+	group, err := findGroup(c)
+	if group == nil {
+		return err
+	}
+
+	return respondWithContacts(c, groupContacts(group.ID))
+}
+
+// @Summary Add group members
+// @Description Adds contacts to a group. Contacts that are already members are skipped
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param members body GroupMembersRequest true "Contacts to add"
+// @Success 200 {object} GroupResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Group or contact not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups/{id}/contacts [post]
+func AddGroupContacts(c *fiber.Ctx) error {
+	group, err := findGroup(c)
+	if group == nil {
+		return err
+	}
+
+	var request GroupMembersRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+	if len(request.ContactIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "contact_ids field is required",
+			Code:    "CONTACT_IDS_REQUIRED",
+		})
+	}
+
+	var existing []uint
+	if err := database.DB.Model(&models.Contact{}).Where("id IN ?", request.ContactIDs).Pluck("id", &existing).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to add group members",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	found := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+	var missing []string
+	rows := make([]models.GroupContact, 0, len(existing))
+	for _, id := range request.ContactIDs {
+		if !found[id] {
+			missing = append(missing, fmt.Sprint(id))
+			continue
+		}
+		rows = append(rows, models.GroupContact{GroupID: group.ID, ContactID: id})
+	}
+	if len(missing) > 0 {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Contacts not found: " + strings.Join(missing, ", "),
+			Code:    "CONTACT_NOT_FOUND",
+		})
+	}
+
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		log.Printf("Error adding group members: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to add group members",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return respondWithGroup(c, *group)
+}
+
+// @Summary Remove group member
+// @Description Removes a contact from a group. The contact itself is kept
+// @Tags groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param contactId path int true "Contact ID"
+// @Success 200 {object} GroupResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Group not found or contact is not a member"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /groups/{id}/contacts/{contactId} [delete]
+func RemoveGroupContact(c *fiber.Ctx) error {
+	group, err := findGroup(c)
+	if group == nil {
+		return err
+	}
+
+	contactID, err := c.ParamsInt("contactId")
+	if err != nil || contactID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid contact ID",
+			Code:    "INVALID_CONTACT_ID",
+		})
+	}
+
+	result := database.DB.Where("group_id = ? AND contact_id = ?", group.ID, contactID).Delete(&models.GroupContact{})
+	if result.Error != nil {
+		log.Printf("Error removing group member: %v", result.Error)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to remove group member",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Contact is not a member of this group",
+			Code:    "CONTACT_NOT_IN_GROUP",
+		})
+	}
+
+	return respondWithGroup(c, *group)
+}
+
+var groupNameConflict = ErrorResponse{
+	Status:  "failed",
+	Message: "A group with this name already exists",
+	Code:    "GROUP_NAME_TAKEN",
+}
+
+// applyGroupRequest validates a request and copies it onto group
+func applyGroupRequest(group *models.Group, request GroupRequest) *ErrorResponse {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Name field is required",
+			Code:    "NAME_REQUIRED",
+		}
+	}
+	if len(name) > 100 {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Name cannot exceed 100 characters",
+			Code:    "NAME_TOO_LONG",
+		}
+	}
+	if len(request.Description) > 255 {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Description cannot exceed 255 characters",
+			Code:    "DESCRIPTION_TOO_LONG",
+		}
+	}
+
+	group.Name = name
+	group.Description = request.Description
+	return nil
+}
+
+// groupNameTaken reports whether another group already uses name
+func groupNameTaken(name string, exceptID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.Group{}).
+		Where("name = ? AND id <> ?", name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// groupContacts scopes a contact query to the members of a group
+func groupContacts(groupID uint) *gorm.DB {
+	return database.DB.Model(&models.Contact{}).
+		Joins("JOIN group_contacts ON group_contacts.contact_id = contacts.id").
+		Where("group_contacts.group_id = ?", groupID)
+}
+
+// respondWithGroup writes a group with its current number of members
+func respondWithGroup(c *fiber.Ctx, group models.Group) error {
+	var count int64
+	if err := database.DB.Model(&models.GroupContact{}).Where("group_id = ?", group.ID).Count(&count).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve group",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(GroupResponse{
+		Status: "success",
+		Data:   GroupData{Group: group, ContactCount: count},
+	})
+}
+
+// findGroup loads the group named by the id path parameter
+func findGroup(c *fiber.Ctx) (*models.Group, error) {
+	return findRecord[models.Group](c, database.DB, "group", "GROUP")
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// createGroupMessages fans a create request out into one message per
+// contact of the group. Each message is personalized: template variables
+// are taken from the request, overridden by the contact's attributes, and
+// name and phone default to the contact's own. The contact's locale is
+// used unless the request names one.
+func createGroupMessages(c *fiber.Ctx, request CreateMessageRequest) error {
+	if request.Phone != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Use either phone or group_id, not both",
+			Code:    "CONFLICTING_RECIPIENT",
+		})
+	}
+
+	var group models.Group
+	if err := database.DB.First(&group, *request.GroupID).Error; err == gorm.ErrRecordNotFound {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Group not found",
+			Code:    "GROUP_NOT_FOUND",
+		})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve group",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	// A group send is created in one request and transaction like a bulk
+	// request, so it is held to the same limit. Larger audiences are sent
+	// as a campaign.
+	var members int64
+	if err := groupContacts(group.ID).Count(&members).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve group members",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if maxMessages := bulkMaxMessages(); members > int64(maxMessages) {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: fmt.Sprintf("Group has %d contacts, at most %d can be messaged in one request; use a campaign instead", members, maxMessages),
+			Code:    "TOO_MANY_MESSAGES",
+		})
+	}
+
+	var contacts []models.Contact
+	if err := groupContacts(group.ID).Order("contacts.id asc").Find(&contacts).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve group members",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if len(contacts) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Group has no contacts",
+			Code:    "GROUP_EMPTY",
+		})
+	}
+
+	// Load the template once for all recipients
+	var template *models.Template
+	if request.TemplateID != nil {
+		var loadErr *ErrorResponse
+		if template, loadErr = loadTemplate(*request.TemplateID); loadErr != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(loadErr)
+		}
+	}
+
+	results := make([]BulkMessageResult, len(contacts))
+	var valid []*models.Message
+	var validIndexes []int
+	for i, contact := range contacts {
+		item := personalizeRequest(request, contact)
+		item.template = template
+
+		message, validationErr := newMessageFromRequest(item)
+		if validationErr != nil {
+			results[i] = BulkMessageResult{
+				Index:     i,
+				ContactID: contact.ID,
+				Status:    "failed",
+				Code:      validationErr.Code,
+				Message:   validationErr.Message,
+			}
+			continue
+		}
+		valid = append(valid, message)
+		validIndexes = append(validIndexes, i)
+	}
+
+	if err := insertMessages(valid); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create messages",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	for i, message := range valid {
+		results[validIndexes[i]] = BulkMessageResult{
+			Index:     validIndexes[i],
+			ContactID: *message.ContactID,
+			Status:    "created",
+			ID:        message.ID,
+		}
+	}
+
+	log.Printf("Created %d of %d messages for group %d", len(valid), len(contacts), group.ID)
+
+	return respondWithBulkResults(c, BulkCreateMessageData{
+		Total:   len(contacts),
+		Created: len(valid),
+		Failed:  len(contacts) - len(valid),
+		Results: results,
+	})
+}
+
+// personalizeRequest builds the create request for one group member
+func personalizeRequest(request CreateMessageRequest, contact models.Contact) CreateMessageRequest {
+	variables := map[string]interface{}{"phone": contact.Phone}
+	if contact.Name != "" {
+		variables["name"] = contact.Name
+	}
+	for name, value := range request.Variables {
+		variables[name] = value
+	}
+	for name, value := range contact.Attributes {
+		variables[name] = value
+	}
+
+	item := request
+	item.GroupID = nil
+	item.Phone = contact.Phone
+	item.Variables = variables
+	if item.Locale == "" {
+		item.Locale = contact.Locale
+	}
+
+	contactID := contact.ID
+	item.contactID = &contactID
+	return item
+}
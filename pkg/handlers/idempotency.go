@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/config"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	idempotencyHeader       = "Idempotency-Key"
+	idempotencyReplayHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 255
+	defaultIdempotencyTTL   = 24 * time.Hour
+)
+
+// Idempotency makes a POST endpoint safe to retry. When the request carries
+// an Idempotency-Key header, the first response is stored and returned
+// again for replays with the same body. Reusing a key with a different
+// body, or while the first request is still running, is a conflict.
+// Requests without the header are passed through unchanged.
+func Idempotency(c *fiber.Ctx) error {
+	key := c.Get(idempotencyHeader)
+	if key == "" {
+		return c.Next()
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Idempotency-Key must be at most 255 characters",
+			Code:    "INVALID_IDEMPOTENCY_KEY",
+		})
+	}
+
+	scope := c.Method() + ":" + c.Route().Path
+	fingerprint := requestFingerprint(c.Body())
+	ttl := idempotencyTTL()
+
+	record, err := cache.ReserveIdempotencyKey(scope, key, fingerprint, ttl)
+	if err != nil {
+		log.Printf("Idempotency error: %v", err)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Idempotency keys are temporarily unavailable, retry later",
+			Code:    "IDEMPOTENCY_UNAVAILABLE",
+		})
+	}
+
+	if record != nil {
+		if record.Fingerprint != fingerprint {
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+				Status:  "failed",
+				Message: "Idempotency-Key was already used with a different request body",
+				Code:    "IDEMPOTENCY_KEY_CONFLICT",
+			})
+		}
+		if !record.Completed {
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+				Status:  "failed",
+				Message: "A request with this Idempotency-Key is still being processed",
+				Code:    "IDEMPOTENCY_KEY_IN_PROGRESS",
+			})
+		}
+
+		c.Set(idempotencyReplayHeader, "true")
+		if record.ContentType != "" {
+			c.Set(fiber.HeaderContentType, record.ContentType)
+		}
+		return c.Status(record.StatusCode).Send(record.Body)
+	}
+
+	if err := c.Next(); err != nil {
+		releaseIdempotencyKey(scope, key)
+		return err
+	}
+
+	// Server errors are not stored so the client can retry with the same key
+	status := c.Response().StatusCode()
+	if status >= fiber.StatusInternalServerError {
+		releaseIdempotencyKey(scope, key)
+		return nil
+	}
+
+	err = cache.CompleteIdempotencyKey(scope, key, cache.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		StatusCode:  status,
+		ContentType: string(c.Response().Header.ContentType()),
+		Body:        append([]byte(nil), c.Response().Body()...),
+	}, ttl)
+	if err != nil {
+		log.Printf("Idempotency error: %v", err)
+		releaseIdempotencyKey(scope, key)
+	}
+
+	return nil
+}
+
+// requestFingerprint hashes the request body. JSON bodies are normalized
+// first so whitespace and key order do not count as a different request.
+func requestFingerprint(body []byte) string {
+	normalized := body
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err == nil {
+		if encoded, err := json.Marshal(value); err == nil {
+			normalized = encoded
+		}
+	}
+
+	hash := sha256.Sum256(normalized)
+	return hex.EncodeToString(hash[:])
+}
+
+func releaseIdempotencyKey(scope, key string) {
+	if err := cache.ReleaseIdempotencyKey(scope, key); err != nil {
+		log.Printf("Idempotency error: %v", err)
+	}
+}
+
+// idempotencyTTL returns how long idempotency keys are remembered
+func idempotencyTTL() time.Duration {
+	return config.Duration("IDEMPOTENCY_TTL", defaultIdempotencyTTL)
+}
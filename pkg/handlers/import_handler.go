@@ -0,0 +1,528 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/config"
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/leader"
+	"fiber-app/pkg/models"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	// importBatchSize is the number of rows inserted and reported per step
+	importBatchSize = 500
+	// defaultImportStaleAfter is how long a job of another instance may go
+	// without progress before it is considered abandoned
+	defaultImportStaleAfter = 15 * time.Minute
+	// defaultImportConcurrency is the number of imports an instance runs at
+	// once
+	defaultImportConcurrency = 2
+)
+
+// importSlots holds one token per running import, set by IMPORT_CONCURRENCY
+var importSlots = make(chan struct{}, config.Int("IMPORT_CONCURRENCY", defaultImportConcurrency))
+
+type ImportJobResponse struct {
+	Status string           `json:"status" example:"success"`
+	Data   models.ImportJob `json:"data"`
+}
+
+type ImportJobsResponse struct {
+	Status string             `json:"status" example:"success"`
+	Data   []models.ImportJob `json:"data"`
+}
+
+// importColumns maps the known CSV columns to their position in a row
+type importColumns struct {
+	phone    int
+	content  int
+	sendAt   int
+	priority int
+	category int
+}
+
+// @Summary Import messages from CSV
+// @Description Uploads a CSV file with phone,content and optional send_at,priority,category columns. Rows are validated and inserted in the background; poll the returned import job for progress
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file"
+// @Success 202 {object} ImportJobResponse "Import started"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 413 {object} ErrorResponse "File larger than 32 MB"
+// @Failure 429 {object} ErrorResponse "IMPORT_CONCURRENCY imports are already running"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /imports/messages [post]
+func ImportMessages(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "CSV file is required in the file field",
+			Code:    "FILE_REQUIRED",
+		})
+	}
+
+	if !strings.EqualFold(filepath.Ext(fileHeader.Filename), ".csv") {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Only .csv files can be imported",
+			Code:    "INVALID_FILE_TYPE",
+		})
+	}
+
+	// Reserve a slot before storing the file, it is handed over to the
+	// import once the job is created
+	select {
+	case importSlots <- struct{}{}:
+	default:
+		return c.Status(fiber.StatusTooManyRequests).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Too many imports are running, retry later",
+			Code:    "IMPORTS_BUSY",
+		})
+	}
+	started := false
+	defer func() {
+		if !started {
+			<-importSlots
+		}
+	}()
+
+	upload, err := os.CreateTemp(os.Getenv("IMPORT_DIR"), "message-import-*.csv")
+	if err != nil {
+		errors.LogError(errors.NewError(errors.ErrorTypeInternal, "Error creating import file", err))
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to store uploaded file",
+			Code:    "IMPORT_STORAGE_ERROR",
+		})
+	}
+	upload.Close()
+
+	if err := c.SaveFile(fileHeader, upload.Name()); err != nil {
+		os.Remove(upload.Name())
+		errors.LogError(errors.NewError(errors.ErrorTypeInternal, "Error saving import file", err))
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to store uploaded file",
+			Code:    "IMPORT_STORAGE_ERROR",
+		})
+	}
+
+	job := models.ImportJob{
+		FileName:   fileHeader.Filename,
+		Status:     models.ImportStatusPending,
+		InstanceID: leader.InstanceID(),
+	}
+	if err := database.DB.Create(&job).Error; err != nil {
+		os.Remove(upload.Name())
+		errors.LogError(errors.NewDatabaseError("Error creating import job", err))
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create import job",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	started = true
+	go func() {
+		defer func() { <-importSlots }()
+		processImport(job, upload.Name())
+	}()
+
+	return c.Status(fiber.StatusAccepted).JSON(ImportJobResponse{
+		Status: "success",
+		Data:   job,
+	})
+}
+
+// @Summary List import jobs
+// @Description Retrieves the most recent message import jobs
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Success 200 {object} ImportJobsResponse "Successful response"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /imports [get]
+func GetImportJobs(c *fiber.Ctx) error {
+	var jobs []models.ImportJob
+	if err := database.DB.Order("created_at desc").Limit(100).Find(&jobs).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve import jobs",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(ImportJobsResponse{
+		Status: "success",
+		Data:   jobs,
+	})
+}
+
+// @Summary Get import job
+// @Description Retrieves the status and progress of a message import job
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Param id path int true "Import job ID"
+// @Success 200 {object} ImportJobResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Import job not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /imports/{id} [get]
+func GetImportJob(c *fiber.Ctx) error {
+	job, err := findImportJob(c)
+	if job == nil {
+		return err
+	}
+
+	return c.JSON(ImportJobResponse{
+		Status: "success",
+		Data:   *job,
+	})
+}
+
+// @Summary Download import error report
+// @Description Downloads the rows rejected by an import job as CSV
+// @Tags imports
+// @Produce text/csv
+// @Param id path int true "Import job ID"
+// @Success 200 {file} file "CSV error report"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Import job not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /imports/{id}/errors [get]
+func GetImportErrors(c *fiber.Ctx) error {
+	job, err := findImportJob(c)
+	if job == nil {
+		return err
+	}
+
+	var importErrors []models.ImportError
+	result := database.DB.Where("import_job_id = ?", job.ID).Order("line_number asc").Find(&importErrors)
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve import errors",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="import-%d-errors.csv"`, job.ID))
+
+	writer := csv.NewWriter(c)
+	writer.Write([]string{"line", "phone", "code", "message"})
+	for _, importError := range importErrors {
+		writer.Write([]string{
+			strconv.Itoa(importError.LineNumber),
+			importError.Phone,
+			importError.Code,
+			importError.Message,
+		})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// findImportJob loads the import job named by the id path parameter
+func findImportJob(c *fiber.Ctx) (*models.ImportJob, error) {
+	return findRecord[models.ImportJob](c, database.DB, "import job", "IMPORT")
+}
+
+// processImport parses the uploaded CSV row by row, inserts valid rows in
+// batches and records progress and rejected rows on the job
+func processImport(job models.ImportJob, path string) {
+	defer os.Remove(path)
+
+	fail := func(err error) {
+		errors.LogError(errors.NewError(errors.ErrorTypeInternal, "Message import failed", err).
+			WithMetadata("importJobId", job.ID))
+		now := time.Now()
+		database.DB.Model(&job).Updates(map[string]interface{}{
+			"status":       models.ImportStatusFailed,
+			"error":        err.Error(),
+			"completed_at": &now,
+		})
+	}
+
+	totalRows, err := countCSVRows(path)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	// The header row is not a data row
+	if totalRows > 0 {
+		totalRows--
+	}
+
+	if err := database.DB.Model(&job).Updates(map[string]interface{}{
+		"status":     models.ImportStatusProcessing,
+		"total_rows": totalRows,
+	}).Error; err != nil {
+		fail(err)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		fail(fmt.Errorf("file is empty"))
+		return
+	} else if err != nil {
+		fail(err)
+		return
+	}
+
+	columns, err := parseImportHeader(header)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	var messages []*models.Message
+	var importErrors []models.ImportError
+	processed, created, failed := 0, 0, 0
+
+	flush := func() error {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if len(messages) > 0 {
+				if err := tx.Create(&messages).Error; err != nil {
+					return err
+				}
+			}
+			if len(importErrors) > 0 {
+				if err := tx.Create(&importErrors).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Model(&job).Updates(map[string]interface{}{
+				"processed_rows": processed,
+				"created_count":  created + len(messages),
+				"failed_count":   failed + len(importErrors),
+			}).Error
+		})
+		if err != nil {
+			return err
+		}
+
+		created += len(messages)
+		failed += len(importErrors)
+		messages = messages[:0]
+		importErrors = importErrors[:0]
+		return nil
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		processed++
+
+		if err != nil {
+			parseErr, ok := err.(*csv.ParseError)
+			if !ok {
+				fail(err)
+				return
+			}
+			importErrors = append(importErrors, models.ImportError{
+				ImportJobID: job.ID,
+				LineNumber:  parseErr.StartLine,
+				Code:        "INVALID_CSV_ROW",
+				Message:     truncate(parseErr.Err.Error(), 255),
+			})
+		} else {
+			line, _ := reader.FieldPos(0)
+			request, rowErr := importRowToRequest(record, columns)
+			if rowErr == nil {
+				var message *models.Message
+				message, rowErr = newMessageFromRequest(request)
+				if rowErr == nil {
+					messages = append(messages, message)
+				}
+			}
+			if rowErr != nil {
+				importErrors = append(importErrors, models.ImportError{
+					ImportJobID: job.ID,
+					LineNumber:  line,
+					Phone:       truncate(request.Phone, 50),
+					Code:        rowErr.Code,
+					Message:     truncate(rowErr.Message, 255),
+				})
+			}
+		}
+
+		if len(messages)+len(importErrors) >= importBatchSize {
+			if err := flush(); err != nil {
+				fail(err)
+				return
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		fail(err)
+		return
+	}
+
+	now := time.Now()
+	database.DB.Model(&job).Updates(map[string]interface{}{
+		"status":       models.ImportStatusCompleted,
+		"total_rows":   processed,
+		"completed_at": &now,
+	})
+
+	if created > 0 {
+		if err := cache.InvalidateMessageLists(); err != nil {
+			log.Printf("Cache invalidation error: %v", err)
+		}
+	}
+
+	log.Printf("Import job %d completed: %d rows, %d created, %d failed", job.ID, processed, created, failed)
+}
+
+// RecoverImportJobs fails the jobs an instance stopped processing, so they
+// do not stay pending or processing forever. Jobs of this instance cannot be
+// running yet when it starts. Other instances may still be importing, so
+// their jobs are only failed once they made no progress for
+// IMPORT_STALE_AFTER. The rows counted in processed_rows were imported; the
+// rest of the file has to be uploaded again.
+func RecoverImportJobs() (int64, error) {
+	now := time.Now()
+	result := database.DB.Model(&models.ImportJob{}).
+		Where("status IN ?", []models.ImportStatus{models.ImportStatusPending, models.ImportStatusProcessing}).
+		Where("instance_id = ? OR updated_at < ?", leader.InstanceID(), now.Add(-importStaleAfter())).
+		Updates(map[string]interface{}{
+			"status":       models.ImportStatusFailed,
+			"error":        "import interrupted by a restart, rows after processed_rows were not imported",
+			"completed_at": &now,
+		})
+	if result.Error != nil {
+		return 0, errors.NewDatabaseError("Error recovering interrupted import jobs", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// importStaleAfter returns how long a job may go without progress before
+// another instance fails it
+func importStaleAfter() time.Duration {
+	return config.Duration("IMPORT_STALE_AFTER", defaultImportStaleAfter)
+}
+
+// parseImportHeader locates the known columns in the header row
+func parseImportHeader(header []string) (importColumns, error) {
+	columns := importColumns{phone: -1, content: -1, sendAt: -1, priority: -1, category: -1}
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "phone":
+			columns.phone = i
+		case "content":
+			columns.content = i
+		case "send_at":
+			columns.sendAt = i
+		case "priority":
+			columns.priority = i
+		case "category":
+			columns.category = i
+		}
+	}
+
+	if columns.phone < 0 || columns.content < 0 {
+		return columns, fmt.Errorf("header must contain phone and content columns")
+	}
+	return columns, nil
+}
+
+// importRowToRequest maps a CSV row onto a create message request
+func importRowToRequest(record []string, columns importColumns) (CreateMessageRequest, *ErrorResponse) {
+	field := func(index int) string {
+		if index < 0 || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	request := CreateMessageRequest{
+		Phone:    field(columns.phone),
+		Content:  field(columns.content),
+		SendAt:   field(columns.sendAt),
+		Category: field(columns.category),
+	}
+
+	if value := field(columns.priority); value != "" {
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return request, &ErrorResponse{
+				Status:  "failed",
+				Message: "Priority must be a number",
+				Code:    "INVALID_PRIORITY",
+			}
+		}
+		request.Priority = &priority
+	}
+
+	return request, nil
+}
+
+// countCSVRows counts the records of a CSV file, including the header
+func countCSVRows(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	count := 0
+	for {
+		_, err := reader.Read()
+		if err == io.EOF {
+			return count, nil
+		}
+		if _, ok := err.(*csv.ParseError); err != nil && !ok {
+			return count, err
+		}
+		count++
+	}
+}
+
+// truncate shortens value to at most length characters
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
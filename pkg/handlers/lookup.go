@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// findRecord loads the record named by the id path parameter through db,
+// which may preload associations. name is the record name used in error
+// messages and code the prefix of the error codes, such as MESSAGE for
+// INVALID_MESSAGE_ID and MESSAGE_NOT_FOUND. If no record is returned, the
+// error response has already been written and the returned error is the
+// result of writing it.
+func findRecord[T any](c *fiber.Ctx, db *gorm.DB, name, code string) (*T, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid " + name + " ID",
+			Code:    "INVALID_" + code + "_ID",
+		})
+	}
+
+	var record T
+	if err := db.First(&record, id).Error; err == gorm.ErrRecordNotFound {
+		return nil, c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: strings.ToUpper(name[:1]) + name[1:] + " not found",
+			Code:    code + "_NOT_FOUND",
+		})
+	} else if err != nil {
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve " + name,
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return &record, nil
+}
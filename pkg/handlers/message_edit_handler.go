@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/errors"
+	"fiber-app/pkg/models"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdateMessageRequest holds the fields of a queued message that can be
+// changed. Omitted fields keep their current value, an empty send_at or
+// expires_at clears it.
+type UpdateMessageRequest struct {
+	Content   *string `json:"content,omitempty" example:"Hello, your order has been shipped."`
+	Phone     *string `json:"phone,omitempty" example:"+905551234567"`
+	SendAt    *string `json:"send_at,omitempty" example:"2025-03-01T09:30:00+03:00"`
+	ExpiresAt *string `json:"expires_at,omitempty" example:"2025-03-01T10:30:00+03:00"`
+}
+
+var messageNotEditable = ErrorResponse{
+	Status:  "failed",
+	Message: "Only queued messages can be changed",
+	Code:    "MESSAGE_NOT_EDITABLE",
+}
+
+// @Summary Update queued message
+// @Description Changes the content, phone or schedule of a message that has not been sent yet. Changing the content of a message rendered from a template detaches it from the template and locale
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Param message body UpdateMessageRequest true "Fields to change"
+// @Success 200 {object} MessageResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Message not found"
+// @Failure 409 {object} ErrorResponse "Message is no longer queued or has expired"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/{id} [patch]
+func UpdateMessage(c *fiber.Ctx) error {
+	message, err := findMessage(c)
+	if message == nil {
+		return err
+	}
+
+	var request UpdateMessageRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	if message.Status != models.MessageStatusQueued {
+		return c.Status(fiber.StatusConflict).JSON(messageNotEditable)
+	}
+
+	// An expired message is only waiting for the cron to mark it, editing
+	// must not give it a new lease of life
+	if message.ExpiresAt != nil && !message.ExpiresAt.After(time.Now()) {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Message has expired and can no longer be edited",
+			Code:    "MESSAGE_EXPIRED",
+		})
+	}
+
+	// Validate the merged message with the same rules as a new message. The
+	// category cannot be edited, it decides which opt-outs apply.
+	merged := CreateMessageRequest{
+		Content:  message.Content,
+		Phone:    message.Phone,
+		SendAt:   formatOptionalTime(message.SendAt),
+		Priority: &message.Priority,
+		Category: string(message.Category),
+	}
+	if message.ExpiresAt != nil {
+		merged.ExpiresAt = message.ExpiresAt.Format(time.RFC3339)
+	}
+	if request.Content != nil {
+		merged.Content = *request.Content
+	}
+	if request.Phone != nil {
+		merged.Phone = *request.Phone
+	}
+	if request.SendAt != nil {
+		merged.SendAt = *request.SendAt
+	}
+	if request.ExpiresAt != nil {
+		merged.ExpiresAt = *request.ExpiresAt
+	}
+
+	updated, validationErr := newMessageFromRequest(merged)
+	if validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	updates := map[string]interface{}{
+		"content":    updated.Content,
+		"encoding":   updated.Encoding,
+		"segments":   updated.Segments,
+		"phone":      updated.Phone,
+		"send_at":    updated.SendAt,
+		"expires_at": updated.ExpiresAt,
+	}
+	// New content is no longer the rendered template, so the message stops
+	// pointing at the template and locale it was created from
+	if updated.Content != message.Content {
+		updates["template_id"] = nil
+		updates["locale"] = nil
+	}
+
+	// Only update while the message is still queued, so a concurrent claim
+	// by the sender wins and the edit is rejected
+	result := database.DB.Model(&models.Message{}).
+		Where("id = ? AND status = ?", message.ID, models.MessageStatusQueued).
+		Updates(updates)
+	if result.Error != nil {
+		errors.LogError(errors.NewDatabaseError("Error updating message", result.Error).
+			WithMetadata("messageId", message.ID))
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to update message",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusConflict).JSON(messageNotEditable)
+	}
+
+	if err := database.DB.First(message, message.ID).Error; err != nil {
+		errors.LogError(errors.NewDatabaseError("Error fetching updated message", err).
+			WithMetadata("messageId", message.ID))
+	}
+	invalidateMessage(message.ID)
+
+	return c.JSON(MessageResponse{
+		Status: "success",
+		Data:   *message,
+	})
+}
+
+// @Summary Cancel queued message
+// @Description Cancels a message that has not been sent yet
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Success 200 {object} MessageResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Message not found"
+// @Failure 409 {object} ErrorResponse "Message is no longer queued"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/{id}/cancel [post]
+func CancelMessage(c *fiber.Ctx) error {
+	message, err := findMessage(c)
+	if message == nil {
+		return err
+	}
+
+	err = message.UpdateStatus(database.DB, models.MessageStatusCancelled, map[string]interface{}{
+		"next_attempt_at": nil,
+	})
+	if err != nil {
+		if errors.IsType(err, errors.ErrorTypeConflict) {
+			return c.Status(fiber.StatusConflict).JSON(messageNotEditable)
+		}
+		errors.LogError(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to cancel message",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	message.NextAttemptAt = nil
+	invalidateMessage(message.ID)
+
+	return c.JSON(MessageResponse{
+		Status: "success",
+		Data:   *message,
+	})
+}
+
+// @Summary Delete queued message
+// @Description Deletes a message that has not been sent yet
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Success 204 "Message deleted"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Message not found"
+// @Failure 409 {object} ErrorResponse "Message is no longer queued"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/{id} [delete]
+func DeleteMessage(c *fiber.Ctx) error {
+	message, err := findMessage(c)
+	if message == nil {
+		return err
+	}
+
+	result := database.DB.
+		Where("id = ? AND status = ?", message.ID, models.MessageStatusQueued).
+		Delete(&models.Message{})
+	if result.Error != nil {
+		errors.LogError(errors.NewDatabaseError("Error deleting message", result.Error).
+			WithMetadata("messageId", message.ID))
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to delete message",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusConflict).JSON(messageNotEditable)
+	}
+
+	invalidateMessage(message.ID)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// findMessage loads the message named by the id path parameter
+func findMessage(c *fiber.Ctx) (*models.Message, error) {
+	return findRecord[models.Message](c, database.DB, "message", "MESSAGE")
+}
+
+// invalidateMessage drops the cached copy and listings after a change
+func invalidateMessage(id uint) {
+	if err := cache.DeleteMessageCache(id); err != nil {
+		log.Printf("Cache invalidation error: %v", err)
+	}
+	if err := cache.InvalidateMessageLists(); err != nil {
+		log.Printf("Cache invalidation error: %v", err)
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -2,17 +2,43 @@ package handlers
 
 import (
 	"fiber-app/pkg/cache"
+	"fiber-app/pkg/config"
 	"fiber-app/pkg/database"
 	"fiber-app/pkg/models"
+	"fiber-app/pkg/phone"
+	"fiber-app/pkg/sms"
+	"fiber-app/pkg/templates"
+	"fmt"
 	"log"
-	"regexp"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
+const defaultMessageMaxSegments = 3
+
 type CreateMessageRequest struct {
-	Content string `json:"content" example:"Hello, your order is being prepared."`
-	Phone   string `json:"phone" example:"+905551234567"`
+	Content    string `json:"content" example:"Hello, your order is being prepared."`
+	Phone      string `json:"phone" example:"+905551234567"`
+	SendAt     string `json:"send_at,omitempty" example:"2025-03-01T09:30:00+03:00"`
+	ExpiresAt  string `json:"expires_at,omitempty" example:"2025-03-01T10:30:00+03:00"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty" example:"300"`
+	Priority   *int   `json:"priority,omitempty" example:"5"`
+	// TemplateID renders the content from a template instead of Content
+	TemplateID *uint                  `json:"template_id,omitempty" example:"1"`
+	Variables  map[string]interface{} `json:"variables,omitempty" swaggertype:"object"`
+	// Locale selects the template variant, e.g. tr-TR or en-US
+	Locale string `json:"locale,omitempty" example:"tr-TR"`
+	// GroupID sends the message to every contact of a group instead of Phone
+	GroupID *uint `json:"group_id,omitempty" example:"3"`
+	// Category is otp, transactional or marketing (the default). Only
+	// marketing messages are blocked by marketing opt-outs
+	Category string `json:"category,omitempty" example:"transactional" enums:"otp,transactional,marketing"`
+
+	// Set when fanning out to a group, so the template is loaded only once
+	template  *models.Template
+	contactID *uint
 }
 
 type SuccessResponse struct {
@@ -36,16 +62,30 @@ type MessagesResponse struct {
 	Data   []models.Message `json:"data"`
 }
 
-var phoneRegex = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+type Pagination struct {
+	Page       int   `json:"page" example:"1"`
+	Limit      int   `json:"limit" example:"20"`
+	Total      int64 `json:"total" example:"42"`
+	TotalPages int   `json:"total_pages" example:"3"`
+}
+
+type MessageListResponse struct {
+	Status     string           `json:"status" example:"success"`
+	Data       []models.Message `json:"data"`
+	Pagination Pagination       `json:"pagination"`
+}
 
 // @Summary Create new message
-// @Description Creates a new message and saves it to the database
+// @Description Creates a new message and saves it to the database. An optional send_at (RFC3339) delays delivery until that time, expires_at or ttl_seconds limit how long the message may still be sent, and priority (0-9) orders delivery. Messages to numbers on the suppression list are rejected unless their category is exempt from the opt-out. Instead of content, template_id and variables render the content from a template
 // @Tags messages
 // @Accept json
 // @Produce json
 // @Param message body CreateMessageRequest true "Message information"
+// @Param Idempotency-Key header string false "Replays with the same key and body return the original response"
 // @Success 201 {object} MessageResponse "Successful response"
 // @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 409 {object} ErrorResponse "Idempotency key reused with a different body or still in progress"
+// @Failure 413 {object} ErrorResponse "Group has more contacts than BULK_MAX_MESSAGES"
 // @Router /messages [post]
 func CreateMessage(c *fiber.Ctx) error {
 	var request CreateMessageRequest
@@ -59,113 +99,323 @@ func CreateMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	// Debug log
-	log.Printf("Received request: %+v", request)
+	if request.GroupID != nil {
+		return createGroupMessages(c, request)
+	}
+
+	message, validationErr := newMessageFromRequest(request)
+	if validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	result := database.DB.Create(message)
+	if result.Error != nil {
+		log.Printf("Error creating message: %v", result.Error)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid data format. Please check your input",
+			Code:    "VALIDATION_ERROR",
+		})
+	}
+
+	log.Printf("Created message %d", message.ID)
+
+	if err := cache.InvalidateMessageLists(); err != nil {
+		log.Printf("Cache invalidation error: %v", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(MessageResponse{
+		Status: "success",
+		Data:   *message,
+	})
+}
+
+// newMessageFromRequest validates a create request and builds the message
+// to be queued
+func newMessageFromRequest(request CreateMessageRequest) (*models.Message, *ErrorResponse) {
+	if request.GroupID != nil {
+		return nil, &ErrorResponse{
+			Status:  "failed",
+			Message: "group_id can only be used when creating a single message",
+			Code:    "GROUP_NOT_ALLOWED",
+		}
+	}
+
+	category := models.MessageCategoryMarketing
+	if request.Category != "" {
+		category = models.MessageCategory(request.Category)
+		if !category.IsValid() {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Category must be one of otp, transactional or marketing",
+				Code:    "INVALID_CATEGORY",
+			}
+		}
+	}
+
+	if request.Locale != "" {
+		locale, err := templates.NormalizeLocale(request.Locale)
+		if err != nil {
+			return nil, invalidLocale(err)
+		}
+		request.Locale = locale
+	}
+
+	if request.TemplateID != nil {
+		if request.Content != "" {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Use either content or template_id, not both",
+				Code:    "CONFLICTING_CONTENT",
+			}
+		}
+
+		template := request.template
+		if template == nil {
+			var loadErr *ErrorResponse
+			if template, loadErr = loadTemplate(*request.TemplateID); loadErr != nil {
+				return nil, loadErr
+			}
+		}
+
+		content, locale, renderErr := renderTemplate(*template, request.Locale, request.Variables)
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		request.Content = content
+		request.Locale = locale
+	}
 
 	// Validate required fields
 	if request.Content == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+		return nil, &ErrorResponse{
 			Status:  "failed",
 			Message: "Content field is required",
 			Code:    "CONTENT_REQUIRED",
-		})
+		}
 	}
 
-	// Content character limit check
-	if len(request.Content) > 120 {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+	// Content may be sent as a concatenated SMS of up to
+	// MESSAGE_MAX_SEGMENTS parts
+	info := sms.Analyze(request.Content)
+	if maxSegments := messageMaxSegments(); info.Segments > maxSegments {
+		return nil, &ErrorResponse{
 			Status:  "failed",
-			Message: "Content field cannot exceed 120 characters",
+			Message: fmt.Sprintf("Content needs %d SMS segments, at most %d are allowed", info.Segments, maxSegments),
 			Code:    "CONTENT_TOO_LONG",
-		})
+		}
 	}
 
 	if request.Phone == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+		return nil, &ErrorResponse{
 			Status:  "failed",
 			Message: "Phone field is required",
 			Code:    "PHONE_REQUIRED",
-		})
+		}
 	}
 
-	// Phone number format validation
-	if !phoneRegex.MatchString(request.Phone) {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+	phoneNumber, phoneErr := normalizePhone(request.Phone)
+	if phoneErr != nil {
+		return nil, phoneErr
+	}
+
+	if suppressedErr := checkSuppression(phoneNumber, category); suppressedErr != nil {
+		return nil, suppressedErr
+	}
+
+	message := &models.Message{
+		Content:    request.Content,
+		Encoding:   string(info.Encoding),
+		Segments:   info.Segments,
+		TemplateID: request.TemplateID,
+		Locale:     request.Locale,
+		ContactID:  request.contactID,
+		Phone:      phoneNumber,
+		Status:     models.MessageStatusQueued,
+		Category:   category,
+		Priority:   models.MessagePriorityNormal,
+	}
+
+	// Optional priority, higher values are sent first
+	if request.Priority != nil {
+		if *request.Priority < models.MessagePriorityLowest || *request.Priority > models.MessagePriorityHighest {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: fmt.Sprintf("Priority must be between %d and %d", models.MessagePriorityLowest, models.MessagePriorityHighest),
+				Code:    "INVALID_PRIORITY",
+			}
+		}
+		message.Priority = *request.Priority
+	}
+
+	// Optional scheduled send time, must carry a timezone
+	if request.SendAt != "" {
+		sendAt, err := time.Parse(time.RFC3339, request.SendAt)
+		if err != nil {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Invalid send_at format. Use RFC3339 with timezone, e.g. 2025-03-01T09:30:00+03:00",
+				Code:    "INVALID_SEND_AT",
+			}
+		}
+		message.SendAt = &sendAt
+	}
+
+	// Optional validity period, given either as an absolute time or a TTL
+	if request.ExpiresAt != "" && request.TTLSeconds != 0 {
+		return nil, &ErrorResponse{
 			Status:  "failed",
-			Message: "Invalid phone number format. Example: +905551234567 or 5551234567",
-			Code:    "INVALID_PHONE_FORMAT",
-		})
+			Message: "Only one of expires_at and ttl_seconds can be set",
+			Code:    "CONFLICTING_EXPIRY",
+		}
 	}
 
-	// Phone number length check
-	if len(request.Phone) > 15 {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+	if request.TTLSeconds < 0 {
+		return nil, &ErrorResponse{
 			Status:  "failed",
-			Message: "Phone number cannot exceed 15 characters",
-			Code:    "PHONE_TOO_LONG",
-		})
+			Message: "ttl_seconds must be a positive number",
+			Code:    "INVALID_TTL",
+		}
 	}
 
-	// Create message
-	message := models.Message{
-		Content: request.Content,
-		Phone:   request.Phone,
-		Status:  false,
+	if request.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, request.ExpiresAt)
+		if err != nil {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Invalid expires_at format. Use RFC3339 with timezone, e.g. 2025-03-01T10:30:00+03:00",
+				Code:    "INVALID_EXPIRES_AT",
+			}
+		}
+		message.ExpiresAt = &expiresAt
+	} else if request.TTLSeconds > 0 {
+		expiresAt := time.Now().Add(time.Duration(request.TTLSeconds) * time.Second)
+		message.ExpiresAt = &expiresAt
 	}
 
-	// Debug log before save
-	log.Printf("Attempting to save message: %+v", message)
+	if message.ExpiresAt != nil {
+		if !message.ExpiresAt.After(time.Now()) {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Message expiry must be in the future",
+				Code:    "INVALID_EXPIRES_AT",
+			}
+		}
+		if message.SendAt != nil && !message.ExpiresAt.After(*message.SendAt) {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: "Message expiry must be after send_at",
+				Code:    "EXPIRES_BEFORE_SEND_AT",
+			}
+		}
+	}
 
-	result := database.DB.Create(&message)
-	if result.Error != nil {
-		log.Printf("Error creating message: %v", result.Error)
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+	return message, nil
+}
+
+// messageMaxSegments returns how many SMS segments one message may use
+func messageMaxSegments() int {
+	return config.Int("MESSAGE_MAX_SEGMENTS", defaultMessageMaxSegments)
+}
+
+// normalizePhone validates a recipient number and returns it in E.164 form.
+// Numbers without a calling code are read as numbers of DEFAULT_COUNTRY.
+func normalizePhone(raw string) (string, *ErrorResponse) {
+	number, err := phone.Parse(raw, phone.DefaultCountry)
+	switch err {
+	case nil:
+	case phone.ErrInvalidLength:
+		return "", &ErrorResponse{
 			Status:  "failed",
-			Message: "Invalid data format. Please check your input",
-			Code:    "VALIDATION_ERROR",
-		})
+			Message: "Phone number has an invalid length for its country",
+			Code:    "INVALID_PHONE_LENGTH",
+		}
+	case phone.ErrInvalidPrefix:
+		return "", &ErrorResponse{
+			Status:  "failed",
+			Message: "Phone number prefix is not in use in its country",
+			Code:    "INVALID_PHONE_PREFIX",
+		}
+	case phone.ErrUnknownCallingCode:
+		return "", &ErrorResponse{
+			Status:  "failed",
+			Message: "Phone number has an invalid country calling code",
+			Code:    "UNSUPPORTED_PHONE_COUNTRY",
+		}
+	default:
+		return "", &ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid phone number format. Example: +905551234567 or 05551234567",
+			Code:    "INVALID_PHONE_FORMAT",
+		}
 	}
 
-	// Debug log after save
-	log.Printf("Successfully created message: %+v", message)
+	// SMS cannot be delivered to landlines
+	if number.Type == phone.TypeLandline {
+		return "", &ErrorResponse{
+			Status:  "failed",
+			Message: "Phone number is a landline and cannot receive SMS",
+			Code:    "PHONE_NOT_MOBILE",
+		}
+	}
 
-	return c.Status(fiber.StatusCreated).JSON(MessageResponse{
-		Status: "success",
-		Data:   message,
-	})
+	return number.E164, nil
 }
 
-// @Summary Get all sent messages
-// @Description Retrieves messages from database where status is true (sent)
+// @Summary List messages
+// @Description Retrieves a page of messages. Without a status filter only sent and delivered messages are returned
 // @Tags messages
 // @Accept json
 // @Produce json
-// @Success 200 {object} MessagesResponse "Successful response"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param status query string false "Comma separated statuses, e.g. queued,failed"
+// @Param phone query string false "Recipient phone number"
+// @Param created_from query string false "Created at or after (RFC3339)"
+// @Param created_to query string false "Created before (RFC3339)"
+// @Param sent_from query string false "Sent at or after (RFC3339)"
+// @Param sent_to query string false "Sent before (RFC3339)"
+// @Success 200 {object} MessageListResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Router /messages [get]
 func GetMessages(c *fiber.Ctx) error {
-	var messages []models.Message
+	query, validationErr := parseMessageListQuery(c)
+	if validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
 
-	// Try from cache first
-	cachedMessages, err := cache.GetMessageCacheWithTimeout(0) // 0 is special key for all messages
+	// Try from cache first, keyed by the normalized query
+	cacheKey, err := cache.MessageListKey(query.cacheKey())
 	if err != nil {
 		log.Printf("Cache error: %v", err)
-	} else if cachedMessages != nil {
-		// Convert cache data to models.Message
-		message := models.Message{
-			ID:      cachedMessages.ID,
-			Content: cachedMessages.Content,
-			Phone:   cachedMessages.Phone,
-			Status:  cachedMessages.Status,
-		}
-		return c.JSON(MessagesResponse{
-			Status: "success",
-			Data:   []models.Message{message},
-		})
+	} else {
+		var cached MessageListResponse
+		found, err := cache.GetMessageList(cacheKey, &cached)
+		if err != nil {
+			log.Printf("Cache error: %v", err)
+		} else if found {
+			return c.JSON(cached)
+		}
 	}
 
 	// If not in cache or error occurred, get from database
-	result := database.DB.Where("status = ?", true).Find(&messages)
+	db := query.apply(database.DB.Model(&models.Message{})).Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve messages",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	var messages []models.Message
+	result := db.Order("created_at desc, id desc").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&messages)
 	if result.Error != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Status:  "failed",
@@ -174,24 +424,115 @@ func GetMessages(c *fiber.Ctx) error {
 		})
 	}
 
+	response := MessageListResponse{
+		Status: "success",
+		Data:   messages,
+		Pagination: Pagination{
+			Page:       query.Page,
+			Limit:      query.Limit,
+			Total:      total,
+			TotalPages: int((total + int64(query.Limit) - 1) / int64(query.Limit)),
+		},
+	}
+
 	// Save successful result to cache
-	if len(messages) > 0 {
+	if cacheKey != "" {
 		go func() {
-			// Save first message to cache
-			cacheData := cache.MessageCache{
-				ID:      messages[0].ID,
-				Content: messages[0].Content,
-				Phone:   messages[0].Phone,
-				Status:  messages[0].Status,
-			}
-			if err := cache.SetMessageCache(0, cacheData); err != nil {
+			if err := cache.SetMessageList(cacheKey, response); err != nil {
 				log.Printf("Cache set error: %v", err)
 			}
 		}()
 	}
 
-	return c.JSON(MessagesResponse{
+	return c.JSON(response)
+}
+
+// @Summary Get message
+// @Description Retrieves a single message by its ID, served from the cache when available
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Success 200 {object} MessageResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Message not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/{id} [get]
+func GetMessage(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid message ID",
+			Code:    "INVALID_MESSAGE_ID",
+		})
+	}
+
+	return respondWithMessage(c, database.DB.Where("id = ?", id), uint(id))
+}
+
+// @Summary Get message by provider ID
+// @Description Retrieves a single message by the ID assigned by the delivery provider, served from the cache when available
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param messageId path string true "Provider message ID"
+// @Success 200 {object} MessageResponse "Successful response"
+// @Failure 404 {object} ErrorResponse "Message not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /messages/provider/{messageId} [get]
+func GetMessageByProviderID(c *fiber.Ctx) error {
+	providerMessageID := c.Params("messageId")
+
+	id, err := cache.GetMessageIDByProviderID(providerMessageID)
+	if err != nil {
+		log.Printf("Cache error: %v", err)
+	}
+
+	return respondWithMessage(c, database.DB.Where("message_id = ?", providerMessageID), id)
+}
+
+// respondWithMessage returns the cached message with the given ID if there
+// is one, otherwise the message matched by query
+func respondWithMessage(c *fiber.Ctx, query *gorm.DB, cachedID uint) error {
+	if cachedID != 0 {
+		cachedMessage, err := cache.GetMessageCacheWithTimeout(cachedID)
+		if err != nil {
+			log.Printf("Cache error: %v", err)
+		} else if cachedMessage != nil {
+			return c.JSON(MessageResponse{
+				Status: "success",
+				Data:   cachedMessage.ToMessage(),
+			})
+		}
+	}
+
+	var message models.Message
+	if err := query.First(&message).Error; err == gorm.ErrRecordNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Message not found",
+			Code:    "MESSAGE_NOT_FOUND",
+		})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve message",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	// Only messages that can no longer change are cached on read. Caching
+	// any other status could overwrite the invalidation of a transition that
+	// committed after the read, serving a stale status until the TTL ends.
+	if message.Status.IsTerminal() {
+		if err := cache.SetMessageCache(message.ID, cache.NewMessageCache(message)); err != nil {
+			log.Printf("Cache set error: %v", err)
+		}
+	}
+
+	return c.JSON(MessageResponse{
 		Status: "success",
-		Data:   messages,
+		Data:   message,
 	})
 }
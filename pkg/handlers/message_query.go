@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"fiber-app/pkg/models"
+	"fiber-app/pkg/phone"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// messageListQuery holds the validated filters of a message list request
+type messageListQuery struct {
+	Page        int
+	Limit       int
+	Statuses    []models.MessageStatus
+	Phone       string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	SentFrom    *time.Time
+	SentTo      *time.Time
+}
+
+// parsePage reads and validates the page and limit query parameters
+func parsePage(c *fiber.Ctx) (Pagination, *ErrorResponse) {
+	page := Pagination{
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", defaultPageSize),
+	}
+
+	if page.Page < 1 {
+		return page, &ErrorResponse{
+			Status:  "failed",
+			Message: "Page must be a positive number",
+			Code:    "INVALID_PAGE",
+		}
+	}
+
+	if page.Limit < 1 || page.Limit > maxPageSize {
+		return page, &ErrorResponse{
+			Status:  "failed",
+			Message: fmt.Sprintf("Limit must be between 1 and %d", maxPageSize),
+			Code:    "INVALID_LIMIT",
+		}
+	}
+
+	return page, nil
+}
+
+// withTotal fills in the total count and number of pages
+func (p Pagination) withTotal(total int64) Pagination {
+	p.Total = total
+	p.TotalPages = int((total + int64(p.Limit) - 1) / int64(p.Limit))
+	return p
+}
+
+// offset is the number of rows before the page
+func (p Pagination) offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// parseMessageListQuery reads paging and filter parameters from the request
+func parseMessageListQuery(c *fiber.Ctx) (*messageListQuery, *ErrorResponse) {
+	page, pageErr := parsePage(c)
+	if pageErr != nil {
+		return nil, pageErr
+	}
+
+	query := &messageListQuery{
+		Page:  page.Page,
+		Limit: page.Limit,
+		Phone: strings.TrimSpace(c.Query("phone")),
+	}
+
+	// Phones are stored in E.164, so match any accepted spelling of the number
+	if query.Phone != "" {
+		if normalized, err := phone.Normalize(query.Phone); err == nil {
+			query.Phone = normalized
+		}
+	}
+
+	if status := c.Query("status"); status != "" {
+		for _, value := range strings.Split(status, ",") {
+			messageStatus := models.MessageStatus(strings.TrimSpace(value))
+			if !messageStatus.IsValid() {
+				return nil, &ErrorResponse{
+					Status:  "failed",
+					Message: fmt.Sprintf("Invalid status filter: %s", value),
+					Code:    "INVALID_STATUS",
+				}
+			}
+			query.Statuses = append(query.Statuses, messageStatus)
+		}
+	} else {
+		query.Statuses = []models.MessageStatus{models.MessageStatusSent, models.MessageStatusDelivered}
+	}
+
+	timeFilters := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+		{"sent_from", &query.SentFrom},
+		{"sent_to", &query.SentTo},
+	}
+	for _, filter := range timeFilters {
+		value := c.Query(filter.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, &ErrorResponse{
+				Status:  "failed",
+				Message: fmt.Sprintf("Invalid %s format. Use RFC3339, e.g. 2025-03-01T09:30:00+03:00", filter.name),
+				Code:    "INVALID_DATE_FILTER",
+			}
+		}
+		*filter.target = &parsed
+	}
+
+	return query, nil
+}
+
+// apply adds the filters to a query on the messages table
+func (q *messageListQuery) apply(db *gorm.DB) *gorm.DB {
+	db = db.Where("status IN ?", q.Statuses)
+	if q.Phone != "" {
+		db = db.Where("phone = ?", q.Phone)
+	}
+	if q.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		db = db.Where("created_at < ?", *q.CreatedTo)
+	}
+	if q.SentFrom != nil {
+		db = db.Where("sent_at >= ?", *q.SentFrom)
+	}
+	if q.SentTo != nil {
+		db = db.Where("sent_at < ?", *q.SentTo)
+	}
+	return db
+}
+
+// cacheKey returns a stable representation of the query for caching
+func (q *messageListQuery) cacheKey() string {
+	statuses := make([]string, len(q.Statuses))
+	for i, status := range q.Statuses {
+		statuses[i] = string(status)
+	}
+	sort.Strings(statuses)
+
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return strings.Join([]string{
+		fmt.Sprintf("page=%d", q.Page),
+		fmt.Sprintf("limit=%d", q.Limit),
+		"status=" + strings.Join(statuses, ","),
+		"phone=" + q.Phone,
+		"created_from=" + formatTime(q.CreatedFrom),
+		"created_to=" + formatTime(q.CreatedTo),
+		"sent_from=" + formatTime(q.SentFrom),
+		"sent_to=" + formatTime(q.SentTo),
+	}, "&")
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/models"
+	"fiber-app/pkg/phone"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// SuppressionRequest adds a phone number to the suppression list. A
+// marketing scope blocks marketing messages only, all blocks every message.
+type SuppressionRequest struct {
+	Phone  string `json:"phone" example:"+905551234567"`
+	Scope  string `json:"scope,omitempty" example:"marketing" enums:"marketing,all"`
+	Reason string `json:"reason,omitempty" example:"Replied STOP"`
+	Source string `json:"source,omitempty" example:"sms_reply"`
+}
+
+type SuppressionResponse struct {
+	Status string             `json:"status" example:"success"`
+	Data   models.Suppression `json:"data"`
+}
+
+type SuppressionListResponse struct {
+	Status     string               `json:"status" example:"success"`
+	Data       []models.Suppression `json:"data"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// @Summary Suppress phone number
+// @Description Adds a phone number to the suppression list. Queued messages to it are marked suppressed instead of being sent; OTP and transactional messages are only blocked by the all scope
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Param suppression body SuppressionRequest true "Suppression"
+// @Success 201 {object} SuppressionResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 409 {object} ErrorResponse "Phone number is already suppressed in this scope"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /suppressions [post]
+func CreateSuppression(c *fiber.Ctx) error {
+	var request SuppressionRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	if request.Phone == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Phone field is required",
+			Code:    "PHONE_REQUIRED",
+		})
+	}
+	phoneNumber, phoneErr := normalizePhone(request.Phone)
+	if phoneErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(phoneErr)
+	}
+
+	scope := models.SuppressionScopeMarketing
+	if request.Scope != "" {
+		scope = models.SuppressionScope(request.Scope)
+		if !scope.IsValid() {
+			return c.Status(fiber.StatusBadRequest).JSON(invalidSuppressionScope)
+		}
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	source := strings.TrimSpace(request.Source)
+	if source == "" {
+		source = "api"
+	}
+	if len(reason) > 255 || len(source) > 50 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Reason cannot exceed 255 and source 50 characters",
+			Code:    "VALUE_TOO_LONG",
+		})
+	}
+
+	suppression := models.Suppression{
+		Phone:  phoneNumber,
+		Scope:  scope,
+		Reason: reason,
+		Source: source,
+	}
+	// The unique index on phone and scope rejects duplicates, also when two
+	// requests add the same number at once
+	if err := database.DB.Create(&suppression).Error; database.IsDuplicateKey(err) {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Phone number is already suppressed in this scope",
+			Code:    "SUPPRESSION_EXISTS",
+		})
+	} else if err != nil {
+		log.Printf("Error creating suppression: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create suppression",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	log.Printf("Suppressed %s for %s messages (source %s)", phoneNumber, scope, source)
+
+	return c.Status(fiber.StatusCreated).JSON(SuppressionResponse{
+		Status: "success",
+		Data:   suppression,
+	})
+}
+
+// @Summary List suppressions
+// @Description Retrieves a page of the suppression list, newest first
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param phone query string false "Phone number"
+// @Param scope query string false "Suppression scope" Enums(marketing, all)
+// @Success 200 {object} SuppressionListResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /suppressions [get]
+func GetSuppressions(c *fiber.Ctx) error {
+	page, pageErr := parsePage(c)
+	if pageErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(pageErr)
+	}
+
+	query := database.DB.Model(&models.Suppression{})
+	if value := strings.TrimSpace(c.Query("phone")); value != "" {
+		if normalized, err := phone.Normalize(value); err == nil {
+			value = normalized
+		}
+		query = query.Where("phone = ?", value)
+	}
+	if value := c.Query("scope"); value != "" {
+		scope := models.SuppressionScope(value)
+		if !scope.IsValid() {
+			return c.Status(fiber.StatusBadRequest).JSON(invalidSuppressionScope)
+		}
+		query = query.Where("scope = ?", scope)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve suppressions",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	suppressions := []models.Suppression{}
+	err := query.Session(&gorm.Session{}).
+		Order("created_at desc, id desc").
+		Offset(page.offset()).Limit(page.Limit).
+		Find(&suppressions).Error
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve suppressions",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(SuppressionListResponse{
+		Status:     "success",
+		Data:       suppressions,
+		Pagination: page.withTotal(total),
+	})
+}
+
+// @Summary Remove suppression
+// @Description Removes a phone number from the suppression list, for example after the recipient opted in again. Messages already suppressed stay suppressed
+// @Tags suppressions
+// @Param id path int true "Suppression ID"
+// @Success 204 "Suppression removed"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Suppression not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /suppressions/{id} [delete]
+func DeleteSuppression(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid suppression ID",
+			Code:    "INVALID_SUPPRESSION_ID",
+		})
+	}
+
+	result := database.DB.Delete(&models.Suppression{}, id)
+	if result.Error != nil {
+		log.Printf("Error deleting suppression: %v", result.Error)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to delete suppression",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Suppression not found",
+			Code:    "SUPPRESSION_NOT_FOUND",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+var invalidSuppressionScope = ErrorResponse{
+	Status:  "failed",
+	Message: "Scope must be marketing or all",
+	Code:    "INVALID_SCOPE",
+}
+
+// checkSuppression rejects a message of the given category to a suppressed
+// phone number
+func checkSuppression(phoneNumber string, category models.MessageCategory) *ErrorResponse {
+	var suppressions []models.Suppression
+	if err := database.DB.Where("phone = ?", phoneNumber).Find(&suppressions).Error; err != nil {
+		log.Printf("Error checking suppression list for %s: %v", phoneNumber, err)
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to check the suppression list",
+			Code:    "DATABASE_ERROR",
+		}
+	}
+
+	for _, suppression := range suppressions {
+		if suppression.Scope.Blocks(category) {
+			return &ErrorResponse{
+				Status:  "failed",
+				Message: "Recipient has opted out of " + string(category) + " messages",
+				Code:    "PHONE_SUPPRESSED",
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,536 @@
+package handlers
+
+import (
+	"fiber-app/pkg/database"
+	"fiber-app/pkg/models"
+	"fiber-app/pkg/templates"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// TemplateRequest creates or replaces a template. Content may contain
+// placeholders such as {{name}} or {{order_id:number}} and is written in
+// Locale, which defaults to DEFAULT_LOCALE.
+type TemplateRequest struct {
+	Name        string `json:"name" example:"order_shipped"`
+	Description string `json:"description,omitempty" example:"Sent when an order leaves the warehouse"`
+	Locale      string `json:"locale,omitempty" example:"tr-TR"`
+	Content     string `json:"content" example:"Merhaba {{name}}, {{order_id:number}} numaralı siparişiniz kargoya verildi."`
+}
+
+// TemplateVariantRequest sets the content of a template in one locale
+type TemplateVariantRequest struct {
+	Content string `json:"content" example:"Hello {{name}}, your order {{order_id:number}} has been shipped."`
+}
+
+// TemplateData is a template together with the variables it expects
+type TemplateData struct {
+	models.Template
+	Variables []templates.Variable `json:"variables"`
+}
+
+type TemplateResponse struct {
+	Status string       `json:"status" example:"success"`
+	Data   TemplateData `json:"data"`
+}
+
+type TemplatesResponse struct {
+	Status string         `json:"status" example:"success"`
+	Data   []TemplateData `json:"data"`
+}
+
+// @Summary Create template
+// @Description Creates a message template. Placeholders are written as {{name}} or {{name:type}} with type string, number or date
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body TemplateRequest true "Template"
+// @Success 201 {object} TemplateResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 409 {object} ErrorResponse "Template name already in use"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /templates [post]
+func CreateTemplate(c *fiber.Ctx) error {
+	var request TemplateRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	template := &models.Template{}
+	if validationErr := applyTemplateRequest(template, request); validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	if taken, err := templateNameTaken(template.Name, 0); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create template",
+			Code:    "DATABASE_ERROR",
+		})
+	} else if taken {
+		return c.Status(fiber.StatusConflict).JSON(templateNameConflict)
+	}
+
+	if err := database.DB.Create(template).Error; err != nil {
+		log.Printf("Error creating template: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to create template",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(TemplateResponse{
+		Status: "success",
+		Data:   newTemplateData(*template),
+	})
+}
+
+// @Summary List templates
+// @Description Retrieves all message templates ordered by name
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Success 200 {object} TemplatesResponse "Successful response"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /templates [get]
+func GetTemplates(c *fiber.Ctx) error {
+	var list []models.Template
+	if err := database.DB.Preload("Variants", orderByLocale).Order("name asc").Find(&list).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve templates",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	data := make([]TemplateData, len(list))
+	for i, template := range list {
+		data[i] = newTemplateData(template)
+	}
+
+	return c.JSON(TemplatesResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+// @Summary Get template
+// @Description Retrieves a message template and the variables it expects
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} TemplateResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Template not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /templates/{id} [get]
+func GetTemplate(c *fiber.Ctx) error {
+	template, err := findTemplate(c)
+	if template == nil {
+		return err
+	}
+
+	return c.JSON(TemplateResponse{
+		Status: "success",
+		Data:   newTemplateData(*template),
+	})
+}
+
+// @Summary Update template
+// @Description Replaces the name, description and content of a template. Messages already created from it keep their rendered content
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param template body TemplateRequest true "Template"
+// @Success 200 {object} TemplateResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Template not found"
+// @Failure 409 {object} ErrorResponse "Template name already in use"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /templates/{id} [put]
+func UpdateTemplate(c *fiber.Ctx) error {
+	template, err := findTemplate(c)
+	if template == nil {
+		return err
+	}
+
+	var request TemplateRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+
+	if validationErr := applyTemplateRequest(template, request); validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	if taken, err := templateNameTaken(template.Name, template.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to update template",
+			Code:    "DATABASE_ERROR",
+		})
+	} else if taken {
+		return c.Status(fiber.StatusConflict).JSON(templateNameConflict)
+	}
+
+	if err := database.DB.Omit("Variants").Save(template).Error; err != nil {
+		log.Printf("Error updating template: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to update template",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(TemplateResponse{
+		Status: "success",
+		Data:   newTemplateData(*template),
+	})
+}
+
+// @Summary Delete template
+// @Description Deletes a template. Messages created from it keep their content. Templates used by a campaign cannot be deleted
+// @Tags templates
+// @Param id path int true "Template ID"
+// @Success 204 "Template deleted"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Template not found"
+// @Failure 409 {object} ErrorResponse "Template is used by a campaign"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /templates/{id} [delete]
+func DeleteTemplate(c *fiber.Ctx) error {
+	template, err := findTemplate(c)
+	if template == nil {
+		return err
+	}
+
+	var campaigns int64
+	if err := database.DB.Model(&models.Campaign{}).Where("template_id = ?", template.ID).Count(&campaigns).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to delete template",
+			Code:    "DATABASE_ERROR",
+		})
+	} else if campaigns > 0 {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Template is used by a campaign",
+			Code:    "TEMPLATE_IN_USE",
+		})
+	}
+
+	if err := database.DB.Delete(template).Error; err != nil {
+		log.Printf("Error deleting template: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to delete template",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Set template variant
+// @Description Creates or replaces the content of a template in one locale, such as en-US
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param locale path string true "Locale, e.g. en-US"
+// @Param variant body TemplateVariantRequest true "Localized content"
+// @Success 200 {object} TemplateResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Template not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /templates/{id}/variants/{locale} [put]
+func SetTemplateVariant(c *fiber.Ctx) error {
+	template, err := findTemplate(c)
+	if template == nil {
+		return err
+	}
+
+	locale, localeErr := templates.NormalizeLocale(c.Params("locale"))
+	if localeErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(invalidLocale(localeErr))
+	}
+	if locale == template.Locale || (template.Locale == "" && locale == templates.DefaultLocale) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "The template content is already written in " + locale + ", update the template instead",
+			Code:    "LOCALE_CONFLICT",
+		})
+	}
+
+	var request TemplateVariantRequest
+	if err := c.BodyParser(&request); err != nil {
+		log.Printf("Error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid JSON format",
+			Code:    "INVALID_JSON",
+		})
+	}
+	if validationErr := validateTemplateContent(request.Content); validationErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErr)
+	}
+
+	variant := models.TemplateVariant{TemplateID: template.ID, Locale: locale}
+	result := database.DB.Where(variant).
+		Assign(models.TemplateVariant{Content: request.Content}).
+		FirstOrCreate(&variant)
+	if result.Error != nil {
+		log.Printf("Error saving template variant: %v", result.Error)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to save template variant",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return respondWithTemplate(c, template.ID)
+}
+
+// @Summary Delete template variant
+// @Description Removes the content of a template in one locale
+// @Tags templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param locale path string true "Locale, e.g. en-US"
+// @Success 200 {object} TemplateResponse "Successful response"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Template or variant not found"
+// @Failure 500 {object} ErrorResponse "Server error"
+// @Router /templates/{id}/variants/{locale} [delete]
+func DeleteTemplateVariant(c *fiber.Ctx) error {
+	template, err := findTemplate(c)
+	if template == nil {
+		return err
+	}
+
+	locale, localeErr := templates.NormalizeLocale(c.Params("locale"))
+	if localeErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(invalidLocale(localeErr))
+	}
+
+	result := database.DB.Where("template_id = ? AND locale = ?", template.ID, locale).Delete(&models.TemplateVariant{})
+	if result.Error != nil {
+		log.Printf("Error deleting template variant: %v", result.Error)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to delete template variant",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Template has no " + locale + " variant",
+			Code:    "VARIANT_NOT_FOUND",
+		})
+	}
+
+	return respondWithTemplate(c, template.ID)
+}
+
+// respondWithTemplate reloads a template with its variants and writes it
+func respondWithTemplate(c *fiber.Ctx, id uint) error {
+	var template models.Template
+	if err := database.DB.Preload("Variants", orderByLocale).First(&template, id).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to retrieve template",
+			Code:    "DATABASE_ERROR",
+		})
+	}
+
+	return c.JSON(TemplateResponse{
+		Status: "success",
+		Data:   newTemplateData(template),
+	})
+}
+
+var templateNameConflict = ErrorResponse{
+	Status:  "failed",
+	Message: "A template with this name already exists",
+	Code:    "TEMPLATE_NAME_TAKEN",
+}
+
+// applyTemplateRequest validates a request and copies it onto template
+func applyTemplateRequest(template *models.Template, request TemplateRequest) *ErrorResponse {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Name field is required",
+			Code:    "NAME_REQUIRED",
+		}
+	}
+	if len(name) > 100 {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Name cannot exceed 100 characters",
+			Code:    "NAME_TOO_LONG",
+		}
+	}
+	if len(request.Description) > 255 {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Description cannot exceed 255 characters",
+			Code:    "DESCRIPTION_TOO_LONG",
+		}
+	}
+	if validationErr := validateTemplateContent(request.Content); validationErr != nil {
+		return validationErr
+	}
+
+	locale := templates.DefaultLocale
+	if request.Locale != "" {
+		normalized, err := templates.NormalizeLocale(request.Locale)
+		if err != nil {
+			return invalidLocale(err)
+		}
+		locale = normalized
+	}
+	for _, variant := range template.Variants {
+		if variant.Locale == locale {
+			return &ErrorResponse{
+				Status:  "failed",
+				Message: "The template already has a " + locale + " variant",
+				Code:    "LOCALE_CONFLICT",
+			}
+		}
+	}
+
+	template.Name = name
+	template.Description = request.Description
+	template.Locale = locale
+	template.Content = request.Content
+	return nil
+}
+
+// validateTemplateContent checks that template content is present and its
+// placeholders are well formed
+func validateTemplateContent(content string) *ErrorResponse {
+	if content == "" {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Content field is required",
+			Code:    "CONTENT_REQUIRED",
+		}
+	}
+	if _, err := templates.Parse(content); err != nil {
+		return &ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid template: " + err.Error(),
+			Code:    "INVALID_TEMPLATE",
+		}
+	}
+	return nil
+}
+
+func invalidLocale(err error) *ErrorResponse {
+	return &ErrorResponse{
+		Status:  "failed",
+		Message: err.Error(),
+		Code:    "INVALID_LOCALE",
+	}
+}
+
+func orderByLocale(db *gorm.DB) *gorm.DB {
+	return db.Order("locale asc")
+}
+
+// templateNameTaken reports whether another template already uses name
+func templateNameTaken(name string, exceptID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.Template{}).
+		Where("name = ? AND id <> ?", name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func newTemplateData(template models.Template) TemplateData {
+	// Stored templates were validated on write
+	variables, _ := templates.Parse(template.Content)
+	if variables == nil {
+		variables = []templates.Variable{}
+	}
+	if template.Variants == nil {
+		template.Variants = []models.TemplateVariant{}
+	}
+	return TemplateData{Template: template, Variables: variables}
+}
+
+// findTemplate loads the template named by the id path parameter with its
+// variants
+func findTemplate(c *fiber.Ctx) (*models.Template, error) {
+	return findRecord[models.Template](c, database.DB.Preload("Variants", orderByLocale), "template", "TEMPLATE")
+}
+
+// loadTemplate loads a template with its variants for rendering
+func loadTemplate(templateID uint) (*models.Template, *ErrorResponse) {
+	var template models.Template
+	if err := database.DB.Preload("Variants").First(&template, templateID).Error; err == gorm.ErrRecordNotFound {
+		return nil, &ErrorResponse{
+			Status:  "failed",
+			Message: "Template not found",
+			Code:    "TEMPLATE_NOT_FOUND",
+		}
+	} else if err != nil {
+		log.Printf("Error loading template %d: %v", templateID, err)
+		return nil, &ErrorResponse{
+			Status:  "failed",
+			Message: "Failed to load template",
+			Code:    "DATABASE_ERROR",
+		}
+	}
+
+	return &template, nil
+}
+
+// renderTemplate fills the variant of template best matching locale with
+// the request variables. It returns the rendered content and the locale it
+// is written in.
+func renderTemplate(template models.Template, locale string, variables map[string]interface{}) (string, string, *ErrorResponse) {
+	localized, selected := templates.Localize(template, locale)
+	content, err := templates.Render(localized, variables)
+	switch err := err.(type) {
+	case nil:
+		return content, selected, nil
+	case *templates.MissingVariablesError:
+		return "", "", &ErrorResponse{
+			Status:  "failed",
+			Message: "Missing template variables: " + strings.Join(err.Names, ", "),
+			Code:    "MISSING_VARIABLES",
+		}
+	case *templates.InvalidVariableError:
+		return "", "", &ErrorResponse{
+			Status:  "failed",
+			Message: err.Error(),
+			Code:    "INVALID_VARIABLE",
+		}
+	default:
+		return "", "", &ErrorResponse{
+			Status:  "failed",
+			Message: "Invalid template: " + err.Error(),
+			Code:    "INVALID_TEMPLATE",
+		}
+	}
+}
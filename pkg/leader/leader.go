@@ -0,0 +1,232 @@
+package leader
+
+import (
+	"context"
+	"fiber-app/pkg/cache"
+	"fiber-app/pkg/config"
+	"fiber-app/pkg/errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultLockKey       = "cron:leader"
+	defaultTTL           = 15 * time.Second
+	defaultRenewInterval = 5 * time.Second
+)
+
+// renewScript extends the lock only if it is still held by this instance
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes the lock only if it is still held by this instance
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+var (
+	mutex         sync.RWMutex
+	enabled       bool
+	isLeader      bool
+	lastError     error
+	started       bool
+	stopCh        chan struct{}
+	instanceID    string
+	lockKey       string
+	ttl           time.Duration
+	renewInterval time.Duration
+)
+
+func init() {
+	enabled = os.Getenv("LEADER_ELECTION_ENABLED") != "false"
+
+	instanceID = os.Getenv("INSTANCE_ID")
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "unknown"
+		}
+		instanceID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	lockKey = os.Getenv("LEADER_LOCK_KEY")
+	if lockKey == "" {
+		lockKey = defaultLockKey
+	}
+
+	ttl = config.Duration("LEADER_TTL", defaultTTL)
+	renewInterval = config.Duration("LEADER_RENEW_INTERVAL", defaultRenewInterval)
+	if renewInterval >= ttl {
+		log.Printf("LEADER_RENEW_INTERVAL must be shorter than LEADER_TTL, using %s", ttl/3)
+		renewInterval = ttl / 3
+	}
+}
+
+// Start begins competing for leadership in the background. It is called
+// when the cron starts. When leader election is disabled this instance is
+// the leader until Stop.
+func Start() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if started {
+		return
+	}
+	started = true
+
+	if !enabled {
+		isLeader = true
+		log.Printf("Leader election disabled, instance %s acts as leader", instanceID)
+		return
+	}
+
+	stopCh = make(chan struct{})
+	go run(stopCh)
+	log.Printf("Leader election started for instance %s", instanceID)
+}
+
+// Stop ends the election loop and releases the lock if this instance holds
+// it, so another instance can take over. It is called when the cron stops.
+func Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if !started {
+		return
+	}
+	started = false
+	lastError = nil
+
+	if !enabled {
+		isLeader = false
+		return
+	}
+
+	close(stopCh)
+	if isLeader {
+		ctx, cancel := context.WithTimeout(cache.Ctx, time.Second*5)
+		defer cancel()
+		if err := releaseScript.Run(ctx, cache.RedisClient, []string{lockKey}, instanceID).Err(); err != nil {
+			errors.LogError(errors.NewCacheError("Error releasing leader lock", err))
+		}
+		isLeader = false
+		log.Printf("Instance %s released leadership", instanceID)
+	}
+}
+
+// Enabled reports whether leadership is decided through Redis. When it is,
+// no instance sends messages while Redis is unreachable.
+func Enabled() bool {
+	return enabled
+}
+
+// LastError returns the error of the most recent election round, or nil if
+// it reached Redis
+func LastError() error {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return lastError
+}
+
+// IsLeader reports whether this instance currently holds leadership
+func IsLeader() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return isLeader
+}
+
+// InstanceID returns the identifier this instance uses in the election
+func InstanceID() string {
+	return instanceID
+}
+
+// CurrentLeader returns the instance ID holding the lock, or an empty string
+// if no instance is leader
+func CurrentLeader() (string, error) {
+	if !enabled {
+		if IsLeader() {
+			return instanceID, nil
+		}
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(cache.Ctx, time.Second*5)
+	defer cancel()
+
+	leader, err := cache.RedisClient.Get(ctx, lockKey).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", errors.NewCacheError("Error reading leader lock", err)
+	}
+	return leader, nil
+}
+
+// run acquires or renews the lock every renewInterval until stopped
+func run(stop chan struct{}) {
+	ticker := time.NewTicker(renewInterval)
+	defer ticker.Stop()
+
+	tick()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			tick()
+		}
+	}
+}
+
+func tick() {
+	ctx, cancel := context.WithTimeout(cache.Ctx, renewInterval)
+	defer cancel()
+
+	leader := IsLeader()
+
+	var acquired bool
+	var err error
+	if leader {
+		var renewed int64
+		renewed, err = renewScript.Run(ctx, cache.RedisClient, []string{lockKey}, instanceID, ttl.Milliseconds()).Int64()
+		acquired = err == nil && renewed == 1
+	} else {
+		acquired, err = cache.RedisClient.SetNX(ctx, lockKey, instanceID, ttl).Result()
+	}
+
+	if err != nil {
+		errors.LogError(errors.NewCacheError("Leader election failed", err).
+			WithMetadata("instanceId", instanceID))
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	// Stop may have run while Redis was being called
+	if !started {
+		return
+	}
+
+	lastError = err
+
+	if acquired != isLeader {
+		isLeader = acquired
+		if acquired {
+			log.Printf("Instance %s became leader", instanceID)
+		} else {
+			log.Printf("Instance %s lost leadership", instanceID)
+		}
+	}
+}
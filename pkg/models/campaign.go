@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+type CampaignStatus string
+
+const (
+	CampaignStatusDraft     CampaignStatus = "draft"
+	CampaignStatusScheduled CampaignStatus = "scheduled"
+	CampaignStatusRunning   CampaignStatus = "running"
+	CampaignStatusPaused    CampaignStatus = "paused"
+	CampaignStatusCompleted CampaignStatus = "completed"
+	CampaignStatusCancelled CampaignStatus = "cancelled"
+)
+
+type CampaignAudience string
+
+const (
+	CampaignAudienceGroup  CampaignAudience = "group"
+	CampaignAudiencePhones CampaignAudience = "phones"
+	CampaignAudienceCSV    CampaignAudience = "csv"
+)
+
+// Campaign sends a template to an audience at a limited rate. Its messages
+// are created when it is started and link back through Message.CampaignID.
+type Campaign struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	Name       string           `json:"name" gorm:"type:varchar(100);not null"`
+	Status     CampaignStatus   `json:"status" gorm:"type:varchar(20);not null;default:'draft';index"`
+	TemplateID uint             `json:"template_id" gorm:"not null;index"`
+	Locale     string           `json:"locale,omitempty" gorm:"type:varchar(20)"`
+	Variables  Attributes       `json:"variables" gorm:"type:json"`
+	Audience   CampaignAudience `json:"audience" gorm:"type:varchar(20);not null"`
+	GroupID    *uint            `json:"group_id,omitempty" gorm:"index"`
+	StartAt    *time.Time       `json:"start_at,omitempty"`
+	// SendRate is the number of messages per minute, 0 sends as fast as
+	// the sender allows
+	SendRate     int        `json:"send_rate" gorm:"not null;default:0"`
+	SkippedCount int        `json:"skipped_count" gorm:"not null;default:0"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	PausedAt     *time.Time `json:"paused_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// CampaignRecipient is a phone number of a phones or CSV audience with the
+// template variables of its row
+type CampaignRecipient struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	CampaignID uint       `json:"campaign_id" gorm:"not null;index"`
+	Phone      string     `json:"phone" gorm:"type:varchar(16);not null"`
+	Variables  Attributes `json:"variables" gorm:"type:json"`
+}
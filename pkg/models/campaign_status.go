@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fiber-app/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// campaignStatusTransitions lists the statuses each campaign status may
+// move to. Statuses without an entry are terminal.
+var campaignStatusTransitions = map[CampaignStatus][]CampaignStatus{
+	CampaignStatusDraft:     {CampaignStatusScheduled, CampaignStatusRunning, CampaignStatusCancelled},
+	CampaignStatusScheduled: {CampaignStatusRunning, CampaignStatusPaused, CampaignStatusCancelled},
+	CampaignStatusRunning:   {CampaignStatusPaused, CampaignStatusCompleted, CampaignStatusCancelled},
+	CampaignStatusPaused:    {CampaignStatusScheduled, CampaignStatusRunning, CampaignStatusCancelled},
+}
+
+// IsValid reports whether the status is one of the known statuses
+func (s CampaignStatus) IsValid() bool {
+	switch s {
+	case CampaignStatusDraft, CampaignStatusScheduled, CampaignStatusRunning, CampaignStatusPaused,
+		CampaignStatusCompleted, CampaignStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed
+func (s CampaignStatus) CanTransitionTo(next CampaignStatus) bool {
+	for _, allowed := range campaignStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateStatus moves the campaign to next and persists it together with the
+// given column updates. Like Message.UpdateStatus the row is only updated
+// while its stored status still matches.
+func (c *Campaign) UpdateStatus(db *gorm.DB, next CampaignStatus, updates map[string]interface{}) error {
+	current := c.Status
+	if !current.CanTransitionTo(next) {
+		return errors.NewConflictError("Invalid campaign status transition", nil).
+			WithMetadata("campaignId", c.ID).
+			WithMetadata("from", current).
+			WithMetadata("to", next)
+	}
+
+	columns := map[string]interface{}{"status": next}
+	for column, value := range updates {
+		columns[column] = value
+	}
+
+	result := db.Model(&Campaign{}).Where("id = ? AND status = ?", c.ID, current).Updates(columns)
+	if result.Error != nil {
+		return errors.NewDatabaseError("Error updating campaign status", result.Error).
+			WithMetadata("campaignId", c.ID).
+			WithMetadata("from", current).
+			WithMetadata("to", next)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NewConflictError("Campaign status changed concurrently", nil).
+			WithMetadata("campaignId", c.ID).
+			WithMetadata("from", current).
+			WithMetadata("to", next)
+	}
+
+	c.Status = next
+	return nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Attributes holds free-form contact data used to personalize templates,
+// stored as a JSON object
+type Attributes map[string]interface{}
+
+// Value implements driver.Valuer
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (a *Attributes) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Attributes", value)
+	}
+	return json.Unmarshal(data, a)
+}
+
+type Contact struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Phone      string     `json:"phone" gorm:"type:varchar(16);not null;uniqueIndex"`
+	Name       string     `json:"name" gorm:"type:varchar(100)"`
+	Locale     string     `json:"locale,omitempty" gorm:"type:varchar(20)"`
+	Timezone   string     `json:"timezone,omitempty" gorm:"type:varchar(64)"`
+	Attributes Attributes `json:"attributes" gorm:"type:json"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Group is a named set of contacts that can be messaged at once
+type Group struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description string    `json:"description,omitempty" gorm:"type:varchar(255)"`
+	Contacts    []Contact `json:"-" gorm:"many2many:group_contacts"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// GroupContact is a row of the group membership join table
+type GroupContact struct {
+	GroupID   uint `json:"group_id" gorm:"primaryKey;autoIncrement:false"`
+	ContactID uint `json:"contact_id" gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (GroupContact) TableName() string {
+	return "group_contacts"
+}
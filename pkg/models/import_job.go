@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+type ImportStatus string
+
+const (
+	ImportStatusPending    ImportStatus = "pending"
+	ImportStatusProcessing ImportStatus = "processing"
+	ImportStatusCompleted  ImportStatus = "completed"
+	ImportStatusFailed     ImportStatus = "failed"
+)
+
+type ImportJob struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	FileName      string       `json:"file_name" gorm:"type:varchar(255);not null"`
+	Status        ImportStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	InstanceID    string       `json:"instance_id" gorm:"type:varchar(100);not null;default:''"`
+	TotalRows     int          `json:"total_rows" gorm:"not null;default:0"`
+	ProcessedRows int          `json:"processed_rows" gorm:"not null;default:0"`
+	CreatedCount  int          `json:"created_count" gorm:"not null;default:0"`
+	FailedCount   int          `json:"failed_count" gorm:"not null;default:0"`
+	Error         string       `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+	CompletedAt   *time.Time   `json:"completed_at,omitempty"`
+}
+
+type ImportError struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	ImportJobID uint   `json:"import_job_id" gorm:"not null;index"`
+	LineNumber  int    `json:"line_number" gorm:"not null"`
+	Phone       string `json:"phone" gorm:"type:varchar(50)"`
+	Code        string `json:"code" gorm:"type:varchar(50);not null"`
+	Message     string `json:"message" gorm:"type:varchar(255);not null"`
+}
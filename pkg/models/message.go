@@ -4,12 +4,37 @@ import (
 	"time"
 )
 
+const (
+	MessagePriorityLowest  = 0
+	MessagePriorityLow     = 2
+	MessagePriorityNormal  = 5
+	MessagePriorityHigh    = 8
+	MessagePriorityHighest = 9
+)
+
 type Message struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Content   string    `json:"content" gorm:"type:varchar(120);not null"`
-	Phone     string    `json:"phone" gorm:"type:varchar(15);not null"`
-	Status    bool      `json:"status" gorm:"default:false"`
-	MessageID string    `json:"message_id" gorm:"type:varchar(100)"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	Content        string          `json:"content" gorm:"type:text;not null"`
+	Encoding       string          `json:"encoding" gorm:"type:varchar(10);not null;default:'gsm7'"`
+	Segments       int             `json:"segments" gorm:"not null;default:1"`
+	TemplateID     *uint           `json:"template_id,omitempty" gorm:"index"`
+	Locale         string          `json:"locale,omitempty" gorm:"type:varchar(20)"`
+	ContactID      *uint           `json:"contact_id,omitempty" gorm:"index"`
+	CampaignID     *uint           `json:"campaign_id,omitempty" gorm:"index"`
+	Phone          string          `json:"phone" gorm:"type:varchar(16);not null"`
+	Status         MessageStatus   `json:"status" gorm:"type:varchar(20);not null;default:'queued';index"`
+	Category       MessageCategory `json:"category" gorm:"type:varchar(20);not null;default:'marketing'"`
+	Priority       int             `json:"priority" gorm:"not null;default:5"`
+	MessageID      string          `json:"message_id" gorm:"type:varchar(100);index"`
+	Attempts       int             `json:"attempts" gorm:"not null;default:0"`
+	LastError      string          `json:"last_error,omitempty" gorm:"type:text"`
+	SendAt         *time.Time      `json:"send_at,omitempty" gorm:"index"`
+	ExpiresAt      *time.Time      `json:"expires_at,omitempty" gorm:"index"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" gorm:"index"`
+	SentAt         *time.Time      `json:"sent_at,omitempty" gorm:"index"`
+	FailedAt       *time.Time      `json:"failed_at,omitempty"`
+	ClaimToken     string          `json:"-" gorm:"type:varchar(64)"`
+	LeaseExpiresAt *time.Time      `json:"-" gorm:"index"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
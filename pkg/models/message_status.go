@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fiber-app/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+type MessageStatus string
+
+const (
+	MessageStatusQueued    MessageStatus = "queued"
+	MessageStatusSending   MessageStatus = "sending"
+	MessageStatusSent      MessageStatus = "sent"
+	MessageStatusDelivered MessageStatus = "delivered"
+	MessageStatusFailed    MessageStatus = "failed"
+	MessageStatusCancelled MessageStatus = "cancelled"
+	MessageStatusExpired   MessageStatus = "expired"
+	// MessageStatusSuppressed marks messages held back because the
+	// recipient is on the suppression list
+	MessageStatusSuppressed MessageStatus = "suppressed"
+)
+
+// messageStatusTransitions lists the statuses each status may move to.
+// Statuses without an entry are terminal.
+var messageStatusTransitions = map[MessageStatus][]MessageStatus{
+	MessageStatusQueued:  {MessageStatusSending, MessageStatusCancelled, MessageStatusExpired, MessageStatusSuppressed},
+	MessageStatusSending: {MessageStatusSent, MessageStatusQueued, MessageStatusFailed},
+	MessageStatusSent:    {MessageStatusDelivered, MessageStatusFailed},
+	MessageStatusFailed:  {MessageStatusQueued},
+}
+
+// IsValid reports whether the status is one of the known statuses
+func (s MessageStatus) IsValid() bool {
+	switch s {
+	case MessageStatusQueued, MessageStatusSending, MessageStatusSent, MessageStatusDelivered,
+		MessageStatusFailed, MessageStatusCancelled, MessageStatusExpired, MessageStatusSuppressed:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether a message in status s can no longer change
+func (s MessageStatus) IsTerminal() bool {
+	return len(messageStatusTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed
+func (s MessageStatus) CanTransitionTo(next MessageStatus) bool {
+	for _, allowed := range messageStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo changes the in-memory status after validating the transition
+func (m *Message) TransitionTo(next MessageStatus) error {
+	if !m.Status.CanTransitionTo(next) {
+		return errors.NewConflictError("Invalid message status transition", nil).
+			WithMetadata("messageId", m.ID).
+			WithMetadata("from", m.Status).
+			WithMetadata("to", next)
+	}
+	m.Status = next
+	return nil
+}
+
+// UpdateStatus transitions the message to next and persists the new status
+// together with the given column updates. The row is only updated while its
+// stored status still matches, so concurrent writers cannot both move the
+// same message out of a status.
+func (m *Message) UpdateStatus(db *gorm.DB, next MessageStatus, updates map[string]interface{}) error {
+	current := m.Status
+	if err := m.TransitionTo(next); err != nil {
+		return err
+	}
+
+	columns := map[string]interface{}{"status": next}
+	for column, value := range updates {
+		columns[column] = value
+	}
+
+	result := db.Model(&Message{}).Where("id = ? AND status = ?", m.ID, current).Updates(columns)
+	if result.Error != nil {
+		m.Status = current
+		return errors.NewDatabaseError("Error updating message status", result.Error).
+			WithMetadata("messageId", m.ID).
+			WithMetadata("from", current).
+			WithMetadata("to", next)
+	}
+	if result.RowsAffected == 0 {
+		m.Status = current
+		return errors.NewConflictError("Message status changed concurrently", nil).
+			WithMetadata("messageId", m.ID).
+			WithMetadata("from", current).
+			WithMetadata("to", next)
+	}
+	return nil
+}
+
+// TransitionMessages moves the messages with the given IDs from one status
+// to another in bulk, persisting the given column updates with the status.
+// Messages that have left from in the meantime are skipped. It returns how
+// many messages were moved.
+func TransitionMessages(db *gorm.DB, ids []uint, from, next MessageStatus, updates map[string]interface{}) (int64, error) {
+	if !from.CanTransitionTo(next) {
+		return 0, errors.NewConflictError("Invalid message status transition", nil).
+			WithMetadata("from", from).
+			WithMetadata("to", next)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	columns := map[string]interface{}{"status": next}
+	for column, value := range updates {
+		columns[column] = value
+	}
+
+	result := db.Model(&Message{}).Where("id IN ? AND status = ?", ids, from).Updates(columns)
+	if result.Error != nil {
+		return 0, errors.NewDatabaseError("Error updating message statuses", result.Error).
+			WithMetadata("from", from).
+			WithMetadata("to", next)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// MessageCategory tells what a message is sent for. Opt-outs from
+// marketing do not block the other categories.
+type MessageCategory string
+
+const (
+	MessageCategoryOTP           MessageCategory = "otp"
+	MessageCategoryTransactional MessageCategory = "transactional"
+	MessageCategoryMarketing     MessageCategory = "marketing"
+)
+
+// IsValid reports whether the category is one of the known categories
+func (c MessageCategory) IsValid() bool {
+	switch c {
+	case MessageCategoryOTP, MessageCategoryTransactional, MessageCategoryMarketing:
+		return true
+	}
+	return false
+}
+
+// SuppressionScope is the set of message categories a suppression blocks
+type SuppressionScope string
+
+const (
+	// SuppressionScopeMarketing blocks marketing messages only, as after a
+	// STOP reply to a campaign
+	SuppressionScopeMarketing SuppressionScope = "marketing"
+	// SuppressionScopeAll blocks every message, including OTPs
+	SuppressionScopeAll SuppressionScope = "all"
+)
+
+// IsValid reports whether the scope is one of the known scopes
+func (s SuppressionScope) IsValid() bool {
+	return s == SuppressionScopeMarketing || s == SuppressionScopeAll
+}
+
+// Blocks reports whether a suppression with scope s applies to messages of
+// the given category
+func (s SuppressionScope) Blocks(category MessageCategory) bool {
+	return s == SuppressionScopeAll || category == MessageCategoryMarketing
+}
+
+// Suppression keeps messages from being sent to a phone number that opted
+// out or must not be contacted
+type Suppression struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	Phone     string           `json:"phone" gorm:"type:varchar(16);not null;uniqueIndex:idx_suppressions_phone_scope"`
+	Scope     SuppressionScope `json:"scope" gorm:"type:varchar(20);not null;default:'marketing';uniqueIndex:idx_suppressions_phone_scope"`
+	Reason    string           `json:"reason,omitempty" gorm:"type:varchar(255)"`
+	Source    string           `json:"source" gorm:"type:varchar(50);not null;default:'api'"`
+	CreatedAt time.Time        `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Template is reusable message content with {{placeholders}} that are
+// filled in when a message is created from it. Content is written in
+// Locale; Variants hold translations into other locales.
+type Template struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	Name        string            `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description string            `json:"description,omitempty" gorm:"type:varchar(255)"`
+	Locale      string            `json:"locale" gorm:"type:varchar(20);not null;default:''"`
+	Content     string            `json:"content" gorm:"type:text;not null"`
+	Variants    []TemplateVariant `json:"variants" gorm:"foreignKey:TemplateID"`
+	CreatedAt   time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TemplateVariant is the content of a template in one locale
+type TemplateVariant struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TemplateID uint      `json:"template_id" gorm:"not null;uniqueIndex:idx_template_variants_locale"`
+	Locale     string    `json:"locale" gorm:"type:varchar(20);not null;uniqueIndex:idx_template_variants_locale"`
+	Content    string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
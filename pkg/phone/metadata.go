@@ -0,0 +1,81 @@
+package phone
+
+import "regexp"
+
+// Country holds the numbering plan data needed to parse and classify the
+// numbers of one country. Patterns match the national significant number,
+// that is the number without calling code and trunk prefix.
+type Country struct {
+	Code        string
+	CallingCode string
+	TrunkPrefix string
+	Lengths     []int
+	Mobile      *regexp.Regexp
+	Landline    *regexp.Regexp
+	// FixedOrMobile matches numbers that cannot be told apart by prefix,
+	// as in the North American Numbering Plan
+	FixedOrMobile *regexp.Regexp
+}
+
+// countries is the bundled numbering plan metadata keyed by ISO 3166-1
+// alpha-2 code
+var countries = map[string]Country{
+	"TR": {
+		Code:        "TR",
+		CallingCode: "90",
+		TrunkPrefix: "0",
+		Lengths:     []int{10},
+		Mobile:      regexp.MustCompile(`^5(?:0[1-7]|[3-5]\d|6[1-9])\d{7}$`),
+		Landline:    regexp.MustCompile(`^[2-4]\d{9}$`),
+	},
+	"US": {
+		Code:          "US",
+		CallingCode:   "1",
+		TrunkPrefix:   "1",
+		Lengths:       []int{10},
+		FixedOrMobile: regexp.MustCompile(`^[2-9]\d{2}[2-9]\d{6}$`),
+	},
+	"GB": {
+		Code:        "GB",
+		CallingCode: "44",
+		TrunkPrefix: "0",
+		Lengths:     []int{9, 10},
+		Mobile:      regexp.MustCompile(`^7[1-57-9]\d{8}$`),
+		Landline:    regexp.MustCompile(`^[12]\d{8,9}$`),
+	},
+	"DE": {
+		Code:        "DE",
+		CallingCode: "49",
+		TrunkPrefix: "0",
+		Lengths:     []int{6, 7, 8, 9, 10, 11},
+		Mobile:      regexp.MustCompile(`^1(?:5\d{9}|[67]\d{8,9})$`),
+		Landline:    regexp.MustCompile(`^[2-9]\d{5,10}$`),
+	},
+	"FR": {
+		Code:        "FR",
+		CallingCode: "33",
+		TrunkPrefix: "0",
+		Lengths:     []int{9},
+		Mobile:      regexp.MustCompile(`^[67]\d{8}$`),
+		Landline:    regexp.MustCompile(`^[1-5]\d{8}$`),
+	},
+	"NL": {
+		Code:        "NL",
+		CallingCode: "31",
+		TrunkPrefix: "0",
+		Lengths:     []int{9},
+		Mobile:      regexp.MustCompile(`^6[1-58]\d{7}$`),
+		Landline:    regexp.MustCompile(`^[1-57]\d{8}$`),
+	},
+}
+
+// byCallingCode indexes countries by calling code. Shared codes resolve to
+// the main country of the plan.
+var byCallingCode = map[string]string{
+	"90": "TR",
+	"1":  "US",
+	"44": "GB",
+	"49": "DE",
+	"33": "FR",
+	"31": "NL",
+}
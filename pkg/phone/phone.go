@@ -0,0 +1,203 @@
+// Package phone normalizes phone numbers to E.164 and validates them
+// against bundled per-country numbering plan metadata
+package phone
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+)
+
+// NumberType classifies a number by the kind of line it belongs to
+type NumberType string
+
+const (
+	TypeMobile   NumberType = "mobile"
+	TypeLandline NumberType = "landline"
+	// TypeFixedOrMobile is used where the numbering plan does not tell
+	// mobile and landline numbers apart
+	TypeFixedOrMobile NumberType = "fixed_or_mobile"
+	// TypeUnknown is used for countries without bundled metadata
+	TypeUnknown NumberType = "unknown"
+)
+
+const fallbackCountry = "TR"
+
+// E.164 numbers have at most 15 digits including the calling code. Shorter
+// than 8 digits is not a subscriber number in any plan.
+const (
+	minE164Digits = 8
+	maxE164Digits = 15
+)
+
+var (
+	ErrEmpty              = errors.New("phone number is empty")
+	ErrInvalidCharacters  = errors.New("phone number contains invalid characters")
+	ErrUnknownCallingCode = errors.New("unknown country calling code")
+	ErrUnsupportedCountry = errors.New("unsupported country")
+	ErrInvalidLength      = errors.New("invalid phone number length for country")
+	ErrInvalidPrefix      = errors.New("phone number prefix is not valid for country")
+)
+
+// Number is a parsed, validated phone number
+type Number struct {
+	E164    string
+	Country string
+	// National is the national significant number without trunk prefix
+	National string
+	Type     NumberType
+}
+
+// DefaultCountry is the country assumed for numbers written without a
+// calling code, set by DEFAULT_COUNTRY
+var DefaultCountry string
+
+func init() {
+	DefaultCountry = fallbackCountry
+	if value := os.Getenv("DEFAULT_COUNTRY"); value != "" {
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if _, ok := countries[value]; ok {
+			DefaultCountry = value
+		} else {
+			log.Printf("Unsupported DEFAULT_COUNTRY %q, using %s", value, fallbackCountry)
+		}
+	}
+}
+
+// Normalize parses raw using the default country and returns its E.164 form
+func Normalize(raw string) (string, error) {
+	number, err := Parse(raw, DefaultCountry)
+	if err != nil {
+		return "", err
+	}
+	return number.E164, nil
+}
+
+// Parse validates raw and converts it to E.164. Numbers starting with "+"
+// or "00" are read as international; anything else is read as a national
+// number of defaultCountry, with or without trunk prefix. Spaces, dashes,
+// dots and parentheses are ignored.
+func Parse(raw, defaultCountry string) (Number, error) {
+	digits, international, err := clean(raw)
+	if err != nil {
+		return Number{}, err
+	}
+
+	if international {
+		return parseInternational(digits)
+	}
+
+	country, ok := countries[strings.ToUpper(defaultCountry)]
+	if !ok {
+		return Number{}, ErrUnsupportedCountry
+	}
+
+	national := digits
+	if country.TrunkPrefix != "" {
+		national = strings.TrimPrefix(digits, country.TrunkPrefix)
+	}
+
+	// Accept international numbers written without "+" or "00", such as
+	// 905551234567, when the number is too long to be national
+	if !country.validLength(len(national)) && strings.HasPrefix(digits, country.CallingCode) {
+		national = strings.TrimPrefix(digits, country.CallingCode)
+	}
+
+	return country.number(national)
+}
+
+// parseInternational validates a number written with its calling code.
+// Numbers of countries without bundled metadata are accepted as they are
+// after the generic E.164 length check, with an unknown type.
+func parseInternational(digits string) (Number, error) {
+	// Calling codes are prefix-free and at most three digits long
+	for length := 1; length <= 3 && length < len(digits); length++ {
+		code, ok := byCallingCode[digits[:length]]
+		if !ok {
+			continue
+		}
+		return countries[code].number(digits[length:])
+	}
+
+	// No calling code starts with 0
+	if digits[0] == '0' {
+		return Number{}, ErrUnknownCallingCode
+	}
+	if len(digits) < minE164Digits || len(digits) > maxE164Digits {
+		return Number{}, ErrInvalidLength
+	}
+	return Number{E164: "+" + digits, Type: TypeUnknown}, nil
+}
+
+// clean strips formatting characters and reports whether the number was
+// written with an international prefix
+func clean(raw string) (string, bool, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false, ErrEmpty
+	}
+
+	international := false
+	if strings.HasPrefix(raw, "+") {
+		international = true
+		raw = raw[1:]
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", false, ErrInvalidCharacters
+		}
+	}
+
+	result := digits.String()
+	if !international && strings.HasPrefix(result, "00") {
+		international = true
+		result = result[2:]
+	}
+	if result == "" {
+		return "", false, ErrEmpty
+	}
+
+	return result, international, nil
+}
+
+func (c Country) validLength(length int) bool {
+	for _, valid := range c.Lengths {
+		if length == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// number validates a national significant number and classifies it
+func (c Country) number(national string) (Number, error) {
+	if !c.validLength(len(national)) {
+		return Number{}, ErrInvalidLength
+	}
+
+	var numberType NumberType
+	switch {
+	case c.Mobile != nil && c.Mobile.MatchString(national):
+		numberType = TypeMobile
+	case c.Landline != nil && c.Landline.MatchString(national):
+		numberType = TypeLandline
+	case c.FixedOrMobile != nil && c.FixedOrMobile.MatchString(national):
+		numberType = TypeFixedOrMobile
+	default:
+		return Number{}, ErrInvalidPrefix
+	}
+
+	return Number{
+		E164:     "+" + c.CallingCode + national,
+		Country:  c.Code,
+		National: national,
+		Type:     numberType,
+	}, nil
+}
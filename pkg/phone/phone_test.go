@@ -0,0 +1,103 @@
+package phone
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		raw            string
+		defaultCountry string
+		want           string
+		country        string
+		numberType     NumberType
+	}{
+		{"E.164", "+905551234567", "TR", "+905551234567", "TR", TypeMobile},
+		{"international with spaces", "+90 555 123 45 67", "TR", "+905551234567", "TR", TypeMobile},
+		{"00 prefix", "00905551234567", "TR", "+905551234567", "TR", TypeMobile},
+		{"national with trunk prefix", "05551234567", "TR", "+905551234567", "TR", TypeMobile},
+		{"national without trunk prefix", "5551234567", "TR", "+905551234567", "TR", TypeMobile},
+		{"formatted national", "(0555) 123-45.67", "TR", "+905551234567", "TR", TypeMobile},
+		{"calling code without plus", "905551234567", "TR", "+905551234567", "TR", TypeMobile},
+		{"default country is case insensitive", "05551234567", "tr", "+905551234567", "TR", TypeMobile},
+		{"TR landline", "02121234567", "TR", "+902121234567", "TR", TypeLandline},
+		{"international overrides default country", "+447911123456", "TR", "+447911123456", "GB", TypeMobile},
+		{"GB national mobile", "07911 123456", "GB", "+447911123456", "GB", TypeMobile},
+		{"US national with trunk prefix", "1 (212) 555-0123", "US", "+12125550123", "US", TypeFixedOrMobile},
+		{"US national", "212-555-0123", "US", "+12125550123", "US", TypeFixedOrMobile},
+		{"DE short landline", "030 123456", "DE", "+4930123456", "DE", TypeLandline},
+		{"DE mobile", "+49 151 23456789", "TR", "+4915123456789", "DE", TypeMobile},
+		{"FR mobile", "06 12 34 56 78", "FR", "+33612345678", "FR", TypeMobile},
+		{"NL mobile", "+31 6 12345678", "TR", "+31612345678", "NL", TypeMobile},
+		{"country without metadata passes through", "+351 912 345 678", "TR", "+351912345678", "", TypeUnknown},
+		{"shortest pass-through number", "+35191234", "TR", "+35191234", "", TypeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			number, err := Parse(tt.raw, tt.defaultCountry)
+			if err != nil {
+				t.Fatalf("Parse(%q, %q) returned error: %v", tt.raw, tt.defaultCountry, err)
+			}
+			if number.E164 != tt.want || number.Country != tt.country || number.Type != tt.numberType {
+				t.Errorf("Parse(%q, %q) = %s %s %s, want %s %s %s", tt.raw, tt.defaultCountry,
+					number.E164, number.Country, number.Type, tt.want, tt.country, tt.numberType)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		raw            string
+		defaultCountry string
+		want           error
+	}{
+		{"empty", "", "TR", ErrEmpty},
+		{"only spaces", "   ", "TR", ErrEmpty},
+		{"only formatting", "+ ()", "TR", ErrEmpty},
+		{"letters", "0555abc4567", "TR", ErrInvalidCharacters},
+		{"plus inside number", "0555+1234567", "TR", ErrInvalidCharacters},
+		{"national too short", "0555123456", "TR", ErrInvalidLength},
+		{"national too long", "055512345678", "TR", ErrInvalidLength},
+		{"international too short for its country", "+90555123456", "TR", ErrInvalidLength},
+		{"unused TR prefix", "05991234567", "TR", ErrInvalidPrefix},
+		{"US area code cannot start with 1", "+11125550123", "TR", ErrInvalidPrefix},
+		{"unsupported default country", "0612345678", "XX", ErrUnsupportedCountry},
+		{"calling code starting with 0", "+0123456789", "TR", ErrUnknownCallingCode},
+		{"pass-through too short", "+3519123", "TR", ErrInvalidLength},
+		{"pass-through too long", "+3519123456789012", "TR", ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			number, err := Parse(tt.raw, tt.defaultCountry)
+			if err != tt.want {
+				t.Errorf("Parse(%q, %q) = %+v, %v; want error %v", tt.raw, tt.defaultCountry, number, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeUsesDefaultCountry(t *testing.T) {
+	previous := DefaultCountry
+	defer func() { DefaultCountry = previous }()
+
+	tests := []struct {
+		country string
+		raw     string
+		want    string
+	}{
+		{"TR", "05551234567", "+905551234567"},
+		{"GB", "07911123456", "+447911123456"},
+		{"FR", "0612345678", "+33612345678"},
+	}
+
+	for _, tt := range tests {
+		DefaultCountry = tt.country
+		got, err := Normalize(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) with %s = %q, %v; want %q", tt.raw, tt.country, got, err, tt.want)
+		}
+	}
+}
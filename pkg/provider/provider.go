@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fiber-app/pkg/models"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultProvider is used when MESSAGE_PROVIDER is not set
+const DefaultProvider = "webhook"
+
+// Provider delivers a message to an outbound gateway and returns the
+// identifier the gateway assigned to it
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, message models.Message) (string, error)
+}
+
+// Factory creates a configured Provider instance
+type Factory func() (Provider, error)
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]Factory)
+)
+
+// Register makes a provider available by name. It is meant to be called
+// from the init function of the package implementing the provider.
+func Register(name string, factory Factory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	name = strings.ToLower(name)
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("provider %q already registered", name))
+	}
+	registry[name] = factory
+}
+
+// New creates the provider registered under the given name
+func New(name string) (Provider, error) {
+	registryMutex.RLock()
+	factory, ok := registry[strings.ToLower(name)]
+	registryMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q, available: %s", name, strings.Join(Names(), ", "))
+	}
+	return factory()
+}
+
+// FromEnv creates the provider selected by the MESSAGE_PROVIDER variable
+func FromEnv() (Provider, error) {
+	name := os.Getenv("MESSAGE_PROVIDER")
+	if name == "" {
+		name = DefaultProvider
+	}
+	return New(name)
+}
+
+// Names returns the registered provider names in sorted order
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
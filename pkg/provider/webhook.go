@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"context"
+	"fiber-app/pkg/models"
+	"fiber-app/pkg/webhook"
+)
+
+func init() {
+	Register("webhook", func() (Provider, error) {
+		sender, err := webhook.NewSender()
+		if err != nil {
+			return nil, err
+		}
+		return &WebhookProvider{sender: sender}, nil
+	})
+}
+
+// WebhookProvider sends messages through the JSON webhook endpoint
+type WebhookProvider struct {
+	sender *webhook.Sender
+}
+
+func (p *WebhookProvider) Name() string {
+	return "webhook"
+}
+
+func (p *WebhookProvider) Send(ctx context.Context, message models.Message) (string, error) {
+	response, err := p.sender.Send(ctx, webhook.Request{
+		To:      message.Phone,
+		Content: message.Content,
+	})
+	if err != nil {
+		return "", err
+	}
+	return response.MessageID, nil
+}
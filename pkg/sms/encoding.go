@@ -0,0 +1,107 @@
+// Package sms detects the encoding of message content and calculates how
+// many SMS segments it needs
+package sms
+
+// Encoding is the character set a message is sent in
+type Encoding string
+
+const (
+	// EncodingGSM7 is the GSM 03.38 default alphabet, 7 bits per character
+	EncodingGSM7 Encoding = "gsm7"
+	// EncodingUCS2 is used when any character is outside GSM-7, 16 bits
+	// per UTF-16 code unit
+	EncodingUCS2 Encoding = "ucs2"
+)
+
+const (
+	gsm7SingleLimit  = 160
+	gsm7SegmentLimit = 153
+	ucs2SingleLimit  = 70
+	ucs2SegmentLimit = 67
+)
+
+// gsm7Basic is the GSM 03.38 basic character set
+var gsm7Basic = map[rune]bool{}
+
+// gsm7Extension holds the characters sent as an escape followed by a code
+// from the extension table, so each counts as two septets
+var gsm7Extension = map[rune]bool{}
+
+func init() {
+	basic := "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	for _, r := range basic {
+		gsm7Basic[r] = true
+	}
+
+	for _, r := range "\f^{}\\[~]|€" {
+		gsm7Extension[r] = true
+	}
+}
+
+// Info describes how a message is encoded and split into segments
+type Info struct {
+	Encoding Encoding
+	// Characters is the number of user visible characters
+	Characters int
+	// Units is the length in septets for GSM-7 or UTF-16 code units for UCS-2
+	Units    int
+	Segments int
+}
+
+// Analyze picks the encoding for content and counts its segments. Empty
+// content still takes one segment.
+func Analyze(content string) Info {
+	info := Info{Encoding: EncodingGSM7}
+
+	var units []int
+	for _, r := range content {
+		info.Characters++
+		switch {
+		case gsm7Basic[r]:
+			units = append(units, 1)
+		case gsm7Extension[r]:
+			units = append(units, 2)
+		default:
+			info.Encoding = EncodingUCS2
+		}
+	}
+
+	if info.Encoding == EncodingUCS2 {
+		units = units[:0]
+		for _, r := range content {
+			if r > 0xFFFF {
+				units = append(units, 2)
+			} else {
+				units = append(units, 1)
+			}
+		}
+		info.Units, info.Segments = segment(units, ucs2SingleLimit, ucs2SegmentLimit)
+		return info
+	}
+
+	info.Units, info.Segments = segment(units, gsm7SingleLimit, gsm7SegmentLimit)
+	return info
+}
+
+// segment packs characters of the given unit sizes into segments. Escape
+// sequences and surrogate pairs are never split across two segments.
+func segment(units []int, singleLimit, segmentLimit int) (int, int) {
+	total := 0
+	for _, size := range units {
+		total += size
+	}
+	if total <= singleLimit {
+		return total, 1
+	}
+
+	segments, used := 1, 0
+	for _, size := range units {
+		if used+size > segmentLimit {
+			segments++
+			used = 0
+		}
+		used += size
+	}
+	return total, segments
+}
@@ -0,0 +1,55 @@
+package sms
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		encoding   Encoding
+		characters int
+		units      int
+		segments   int
+	}{
+		{"empty content takes one segment", "", EncodingGSM7, 0, 0, 1},
+		{"plain GSM-7", "Hello!", EncodingGSM7, 6, 6, 1},
+		{"GSM-7 single limit", strings.Repeat("a", 160), EncodingGSM7, 160, 160, 1},
+		{"GSM-7 one over the single limit", strings.Repeat("a", 161), EncodingGSM7, 161, 161, 2},
+		{"GSM-7 two full parts", strings.Repeat("a", 306), EncodingGSM7, 306, 306, 2},
+		{"GSM-7 third part", strings.Repeat("a", 307), EncodingGSM7, 307, 307, 3},
+		{"GSM-7 basic accents", "àäöñüÄÖÑÜé", EncodingGSM7, 10, 10, 1},
+		{"extension characters count two septets", "€[]{}", EncodingGSM7, 5, 10, 1},
+		{"extension character pushes over the single limit", strings.Repeat("a", 159) + "€", EncodingGSM7, 160, 161, 2},
+		{"extension character is not split at a part boundary",
+			strings.Repeat("a", 152) + "€" + strings.Repeat("a", 152), EncodingGSM7, 305, 306, 3},
+		{"extension character fitting a part exactly",
+			strings.Repeat("a", 151) + "€" + strings.Repeat("a", 153), EncodingGSM7, 305, 306, 2},
+		{"Turkish character forces UCS-2", "Merhaba dünya ğ", EncodingUCS2, 15, 15, 1},
+		{"dotless i forces UCS-2", "ı", EncodingUCS2, 1, 1, 1},
+		{"extension characters count one unit in UCS-2", "ş€", EncodingUCS2, 2, 2, 1},
+		{"UCS-2 single limit", strings.Repeat("ş", 70), EncodingUCS2, 70, 70, 1},
+		{"UCS-2 one over the single limit", strings.Repeat("ş", 71), EncodingUCS2, 71, 71, 2},
+		{"UCS-2 two full parts", strings.Repeat("ş", 134), EncodingUCS2, 134, 134, 2},
+		{"UCS-2 third part", strings.Repeat("ş", 135), EncodingUCS2, 135, 135, 3},
+		{"surrogate pair counts two units", "😀", EncodingUCS2, 1, 2, 1},
+		{"surrogate pair at the single limit", strings.Repeat("ş", 68) + "😀", EncodingUCS2, 69, 70, 1},
+		{"surrogate pair pushes over the single limit", strings.Repeat("ş", 69) + "😀", EncodingUCS2, 70, 71, 2},
+		{"surrogate pair is not split at a part boundary",
+			strings.Repeat("ş", 66) + "😀" + strings.Repeat("ş", 66), EncodingUCS2, 133, 134, 3},
+		{"surrogate pair fitting a part exactly",
+			strings.Repeat("ş", 65) + "😀" + strings.Repeat("ş", 67), EncodingUCS2, 133, 134, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze(tt.content)
+			want := Info{Encoding: tt.encoding, Characters: tt.characters, Units: tt.units, Segments: tt.segments}
+			if got != want {
+				t.Errorf("Analyze() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package templates
+
+import (
+	"fiber-app/pkg/models"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const fallbackLocale = "tr-TR"
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(?:-[A-Z]{2})?$`)
+
+// DefaultLocale is used when a request names no locale or none of the
+// template variants match it, set by DEFAULT_LOCALE
+var DefaultLocale string
+
+func init() {
+	DefaultLocale = fallbackLocale
+	if value := os.Getenv("DEFAULT_LOCALE"); value != "" {
+		if locale, err := NormalizeLocale(value); err == nil {
+			DefaultLocale = locale
+		} else {
+			log.Printf("Invalid DEFAULT_LOCALE %q, using %s", value, fallbackLocale)
+		}
+	}
+}
+
+// NormalizeLocale converts tags such as "tr_tr" or "EN-us" to the canonical
+// form "tr-TR" or "en-US". A bare language such as "tr" is also accepted.
+func NormalizeLocale(locale string) (string, error) {
+	language, region, _ := strings.Cut(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	normalized := strings.ToLower(language)
+	if region != "" {
+		normalized += "-" + strings.ToUpper(region)
+	}
+
+	if !localePattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid locale %q, expected a tag such as tr-TR or en", locale)
+	}
+	return normalized, nil
+}
+
+// language returns the language part of a normalized locale
+func language(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// ResolveLocale picks the best of the available locales for a request. The
+// requested locale is tried first, then each fallback in order. For every
+// candidate an exact match wins over a variant of the same language, so a
+// request for "tr" or "tr-CY" is served by "tr-TR". It reports false when
+// nothing matches.
+func ResolveLocale(requested string, available []string, fallbacks ...string) (string, bool) {
+	for _, candidate := range append([]string{requested}, fallbacks...) {
+		if candidate == "" {
+			continue
+		}
+		for _, locale := range available {
+			if locale == candidate {
+				return locale, true
+			}
+		}
+		for _, locale := range available {
+			if language(locale) == language(candidate) {
+				return locale, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Localize returns the content of template for a requested locale and the
+// locale that content is written in. The variant best matching locale is
+// used, falling back to DefaultLocale and then to the template's own
+// content.
+func Localize(template models.Template, locale string) (string, string) {
+	// Templates stored before locales were introduced are written in the
+	// default locale
+	baseLocale := template.Locale
+	if baseLocale == "" {
+		baseLocale = DefaultLocale
+	}
+
+	contents := map[string]string{baseLocale: template.Content}
+	available := []string{baseLocale}
+	for _, variant := range template.Variants {
+		contents[variant.Locale] = variant.Content
+		available = append(available, variant.Locale)
+	}
+
+	selected, ok := ResolveLocale(locale, available, DefaultLocale)
+	if !ok {
+		selected = baseLocale
+	}
+	return contents[selected], selected
+}
@@ -0,0 +1,123 @@
+package templates
+
+import (
+	"fiber-app/pkg/models"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"tr-TR", "tr-TR"},
+		{"tr_tr", "tr-TR"},
+		{"EN-us", "en-US"},
+		{" de-de ", "de-DE"},
+		{"tr", "tr"},
+		{"FIL", "fil"},
+		{"tr-", "tr"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeLocale(tt.locale)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeLocale(%q) = %q, %v; want %q", tt.locale, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizeLocaleErrors(t *testing.T) {
+	for _, locale := range []string{"", "t", "turkish", "tr-TUR", "tr-1", "tr-TR-x", "-TR"} {
+		if got, err := NormalizeLocale(locale); err == nil {
+			t.Errorf("NormalizeLocale(%q) = %q, want an error", locale, got)
+		}
+	}
+}
+
+func TestResolveLocale(t *testing.T) {
+	available := []string{"tr-TR", "en-US", "en-GB", "de-DE"}
+
+	tests := []struct {
+		name      string
+		requested string
+		fallbacks []string
+		want      string
+		found     bool
+	}{
+		{"exact match", "en-GB", nil, "en-GB", true},
+		{"exact match wins over an earlier variant of the language", "en-GB", []string{"tr-TR"}, "en-GB", true},
+		{"bare language uses the first variant of the language", "en", nil, "en-US", true},
+		{"other region of the language", "de-AT", nil, "de-DE", true},
+		{"same language wins over an exact fallback", "de-CH", []string{"tr-TR"}, "de-DE", true},
+		{"fallback when the language is unavailable", "fr-FR", []string{"tr-TR"}, "tr-TR", true},
+		{"fallbacks are tried in order", "fr-FR", []string{"it-IT", "de-DE", "tr-TR"}, "de-DE", true},
+		{"fallback matches by language", "fr-FR", []string{"en"}, "en-US", true},
+		{"empty request uses the fallback", "", []string{"tr-TR"}, "tr-TR", true},
+		{"empty fallbacks are skipped", "fr-FR", []string{"", "de-DE"}, "de-DE", true},
+		{"nothing matches", "fr-FR", []string{"it-IT"}, "", false},
+		{"nothing requested", "", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := ResolveLocale(tt.requested, available, tt.fallbacks...)
+			if got != tt.want || found != tt.found {
+				t.Errorf("ResolveLocale(%q, %v) = %q, %v; want %q, %v", tt.requested, tt.fallbacks, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	previous := DefaultLocale
+	DefaultLocale = "tr-TR"
+	defer func() { DefaultLocale = previous }()
+
+	turkish := models.Template{
+		Locale:   "tr-TR",
+		Content:  "Merhaba",
+		Variants: []models.TemplateVariant{{Locale: "en-US", Content: "Hello"}},
+	}
+	english := models.Template{
+		Locale:   "en-US",
+		Content:  "Hello",
+		Variants: []models.TemplateVariant{{Locale: "de-DE", Content: "Hallo"}},
+	}
+	englishWithTurkish := models.Template{
+		Locale:   "en-US",
+		Content:  "Hello",
+		Variants: []models.TemplateVariant{{Locale: "tr-TR", Content: "Merhaba"}},
+	}
+	legacy := models.Template{
+		Content:  "Merhaba",
+		Variants: []models.TemplateVariant{{Locale: "en-US", Content: "Hello"}},
+	}
+
+	tests := []struct {
+		name     string
+		template models.Template
+		locale   string
+		want     string
+		selected string
+	}{
+		{"variant", turkish, "en-US", "Hello", "en-US"},
+		{"variant of the same language", turkish, "en-GB", "Hello", "en-US"},
+		{"template content", turkish, "tr-TR", "Merhaba", "tr-TR"},
+		{"no locale uses the default locale", turkish, "", "Merhaba", "tr-TR"},
+		{"unknown locale uses the default locale", turkish, "fr-FR", "Merhaba", "tr-TR"},
+		{"default locale variant wins over the template content", englishWithTurkish, "fr-FR", "Merhaba", "tr-TR"},
+		{"template content when the default locale is unavailable", english, "fr-FR", "Hello", "en-US"},
+		{"template without a locale is written in the default locale", legacy, "tr-CY", "Merhaba", "tr-TR"},
+		{"variant of a template without a locale", legacy, "en", "Hello", "en-US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, selected := Localize(tt.template, tt.locale)
+			if content != tt.want || selected != tt.selected {
+				t.Errorf("Localize(%q) = %q, %q; want %q, %q", tt.locale, content, selected, tt.want, tt.selected)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package templates parses message templates and renders them with typed
+// variables. Placeholders are written as {{name}} or {{name:type}}, where
+// type is one of string (the default), number or date.
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VariableType restricts the values a placeholder accepts
+type VariableType string
+
+const (
+	TypeString VariableType = "string"
+	TypeNumber VariableType = "number"
+	TypeDate   VariableType = "date"
+)
+
+const dateLayout = "2006-01-02"
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*(?::\s*([a-z]+)\s*)?\}\}`)
+
+// Variable is a placeholder used by a template
+type Variable struct {
+	Name string       `json:"name" example:"order_id"`
+	Type VariableType `json:"type" example:"number"`
+}
+
+// MissingVariablesError lists the variables a render call did not provide
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("missing template variables: %s", strings.Join(e.Names, ", "))
+}
+
+// InvalidVariableError reports a value that does not match its
+// placeholder type
+type InvalidVariableError struct {
+	Name string
+	Type VariableType
+}
+
+func (e *InvalidVariableError) Error() string {
+	return fmt.Sprintf("template variable %s must be a %s", e.Name, e.Type)
+}
+
+// Parse validates the placeholder syntax of content and returns the
+// variables it uses in order of first appearance
+func Parse(content string) ([]Variable, error) {
+	var variables []Variable
+	types := make(map[string]VariableType)
+
+	matches := placeholderPattern.FindAllStringSubmatchIndex(content, -1)
+	last := 0
+	for _, match := range matches {
+		if strings.Contains(content[last:match[0]], "{{") {
+			return nil, fmt.Errorf("invalid placeholder near %q", excerpt(content[last:match[0]]))
+		}
+		last = match[1]
+
+		name := content[match[2]:match[3]]
+		variableType := TypeString
+		if match[4] >= 0 {
+			variableType = VariableType(content[match[4]:match[5]])
+		}
+		switch variableType {
+		case TypeString, TypeNumber, TypeDate:
+		default:
+			return nil, fmt.Errorf("unknown type %q for variable %s", variableType, name)
+		}
+
+		if existing, ok := types[name]; ok {
+			if existing != variableType {
+				return nil, fmt.Errorf("variable %s is used as both %s and %s", name, existing, variableType)
+			}
+			continue
+		}
+		types[name] = variableType
+		variables = append(variables, Variable{Name: name, Type: variableType})
+	}
+	if strings.Contains(content[last:], "{{") {
+		return nil, fmt.Errorf("invalid placeholder near %q", excerpt(content[last:]))
+	}
+
+	return variables, nil
+}
+
+// Render substitutes values into content. Every placeholder must have a
+// value of the matching type; extra values are ignored.
+func Render(content string, values map[string]interface{}) (string, error) {
+	variables, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	var invalid error
+	formatted := make(map[string]string, len(variables))
+	for _, variable := range variables {
+		value, ok := values[variable.Name]
+		if !ok || value == nil {
+			missing = append(missing, variable.Name)
+			continue
+		}
+
+		text, ok := format(value, variable.Type)
+		if !ok && invalid == nil {
+			invalid = &InvalidVariableError{Name: variable.Name, Type: variable.Type}
+		}
+		formatted[variable.Name] = text
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", &MissingVariablesError{Names: missing}
+	}
+	if invalid != nil {
+		return "", invalid
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		return formatted[name]
+	}), nil
+}
+
+// format converts a JSON decoded value to text for a placeholder type
+func format(value interface{}, variableType VariableType) (string, bool) {
+	switch variableType {
+	case TypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		case int:
+			return strconv.Itoa(v), true
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return v, true
+			}
+		}
+		return "", false
+	case TypeDate:
+		v, ok := value.(string)
+		if !ok {
+			return "", false
+		}
+		if date, err := time.Parse(time.RFC3339, v); err == nil {
+			return date.Format(dateLayout), true
+		}
+		if _, err := time.Parse(dateLayout, v); err == nil {
+			return v, true
+		}
+		return "", false
+	default:
+		switch v := value.(type) {
+		case string:
+			return v, true
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		case bool:
+			return strconv.FormatBool(v), true
+		}
+		return "", false
+	}
+}
+
+func excerpt(text string) string {
+	if index := strings.Index(text, "{{"); index >= 0 {
+		text = text[index:]
+	}
+	if len(text) > 20 {
+		text = text[:20]
+	}
+	return text
+}
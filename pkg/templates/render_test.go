@@ -0,0 +1,137 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Variable
+	}{
+		{"no placeholders", "Hello there", nil},
+		{"untyped placeholder is a string", "Hi {{name}}", []Variable{{Name: "name", Type: TypeString}}},
+		{"typed placeholders in order of appearance", "Order {{order_id:number}} ships on {{ship_date:date}} to {{name:string}}",
+			[]Variable{{Name: "order_id", Type: TypeNumber}, {Name: "ship_date", Type: TypeDate}, {Name: "name", Type: TypeString}}},
+		{"spaces inside braces", "Hi {{ name : string }}", []Variable{{Name: "name", Type: TypeString}}},
+		{"repeated placeholder is listed once", "{{name}}, yes you {{name}}", []Variable{{Name: "name", Type: TypeString}}},
+		{"explicit and default string agree", "{{name}} {{name:string}}", []Variable{{Name: "name", Type: TypeString}}},
+		{"single braces are text", "Use {code} or }} freely", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.content)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.content, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown type", "Paid {{amount:money}}"},
+		{"conflicting types", "{{id:number}} and {{id:date}}"},
+		{"conflicting with the default type", "{{id}} and {{id:number}}"},
+		{"unclosed placeholder", "Hi {{name"},
+		{"unclosed placeholder before a valid one", "Hi {{name and {{other}}"},
+		{"name starting with a digit", "Code {{1code}}"},
+		{"empty placeholder", "Hi {{}}"},
+		{"invalid characters in name", "Hi {{first-name}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if variables, err := Parse(tt.content); err == nil {
+				t.Errorf("Parse(%q) = %+v, want an error", tt.content, variables)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		values  map[string]interface{}
+		want    string
+	}{
+		{"string", "Hi {{name}}", map[string]interface{}{"name": "Ayşe"}, "Hi Ayşe"},
+		{"repeated placeholder", "{{name}}, {{ name }}!", map[string]interface{}{"name": "Ali"}, "Ali, Ali!"},
+		{"extra values are ignored", "Hi {{name}}", map[string]interface{}{"name": "Ali", "unused": 1.0}, "Hi Ali"},
+		{"number from JSON", "Order {{id:number}}", map[string]interface{}{"id": 1234567.0}, "Order 1234567"},
+		{"fractional number", "Total {{total:number}}", map[string]interface{}{"total": 12.5}, "Total 12.5"},
+		{"number from int", "Order {{id:number}}", map[string]interface{}{"id": 42}, "Order 42"},
+		{"numeric string keeps its form", "Total {{total:number}}", map[string]interface{}{"total": "12.50"}, "Total 12.50"},
+		{"date", "On {{day:date}}", map[string]interface{}{"day": "2024-03-05"}, "On 2024-03-05"},
+		{"RFC 3339 timestamp is shortened to a date", "On {{day:date}}", map[string]interface{}{"day": "2024-03-05T10:30:00+03:00"}, "On 2024-03-05"},
+		{"number in a string placeholder", "Code {{code}}", map[string]interface{}{"code": 1234.0}, "Code 1234"},
+		{"bool in a string placeholder", "Opted in: {{optin}}", map[string]interface{}{"optin": true}, "Opted in: true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.content, tt.values)
+			if err != nil {
+				t.Fatalf("Render(%q) returned error: %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		values  map[string]interface{}
+		want    error
+	}{
+		{"missing variable", "Hi {{name}}", nil,
+			&MissingVariablesError{Names: []string{"name"}}},
+		{"null counts as missing", "Hi {{name}}", map[string]interface{}{"name": nil},
+			&MissingVariablesError{Names: []string{"name"}}},
+		{"missing variables are sorted", "{{zip}} {{city}} {{address}}", map[string]interface{}{"city": "Ankara"},
+			&MissingVariablesError{Names: []string{"address", "zip"}}},
+		{"missing is reported before invalid", "{{id:number}} {{name}}", map[string]interface{}{"id": "abc"},
+			&MissingVariablesError{Names: []string{"name"}}},
+		{"text for a number", "Order {{id:number}}", map[string]interface{}{"id": "twelve"},
+			&InvalidVariableError{Name: "id", Type: TypeNumber}},
+		{"bool for a number", "Order {{id:number}}", map[string]interface{}{"id": true},
+			&InvalidVariableError{Name: "id", Type: TypeNumber}},
+		{"wrong date format", "On {{day:date}}", map[string]interface{}{"day": "05.03.2024"},
+			&InvalidVariableError{Name: "day", Type: TypeDate}},
+		{"number for a date", "On {{day:date}}", map[string]interface{}{"day": 20240305.0},
+			&InvalidVariableError{Name: "day", Type: TypeDate}},
+		{"object for a string", "Hi {{name}}", map[string]interface{}{"name": map[string]interface{}{"first": "Ali"}},
+			&InvalidVariableError{Name: "name", Type: TypeString}},
+		{"first invalid variable is reported", "{{a:number}} {{b:date}}", map[string]interface{}{"a": "x", "b": "y"},
+			&InvalidVariableError{Name: "a", Type: TypeNumber}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.content, tt.values)
+			if !reflect.DeepEqual(err, tt.want) {
+				t.Errorf("Render(%q) = %q, %v; want error %v", tt.content, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderInvalidTemplate(t *testing.T) {
+	if _, err := Render("Hi {{name:money}}", map[string]interface{}{"name": "Ali"}); err == nil {
+		t.Error("Render with an unknown placeholder type succeeded, want an error")
+	}
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fiber-app/pkg/config"
+	"fiber-app/pkg/errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type Mode string
+
+const (
+	// ModeLive sends requests to the configured webhook URL
+	ModeLive Mode = "live"
+	// ModeSimulated skips the HTTP call and returns a generated response
+	ModeSimulated Mode = "simulated"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+
+	// maxErrorBodySize limits how much of a failed response is kept for logging
+	maxErrorBodySize = 1024
+)
+
+type Request struct {
+	To      string `json:"to"`
+	Content string `json:"content"`
+}
+
+type Response struct {
+	Message   string `json:"message"`
+	MessageID string `json:"messageId"`
+}
+
+// Sender delivers messages to the webhook endpoint
+type Sender struct {
+	URL     string
+	AuthKey string
+	Mode    Mode
+	Client  *http.Client
+}
+
+// NewSender creates a Sender configured from environment variables. Live
+// mode, the default, requires WEBHOOK_URL; there is no fallback endpoint, so
+// message content is never sent anywhere that was not configured.
+func NewSender() (*Sender, error) {
+	mode := ModeLive
+	if value := strings.ToLower(strings.TrimSpace(os.Getenv("WEBHOOK_MODE"))); value != "" {
+		mode = Mode(value)
+	}
+	if mode != ModeLive && mode != ModeSimulated {
+		return nil, fmt.Errorf("invalid WEBHOOK_MODE %q, expected %s or %s", os.Getenv("WEBHOOK_MODE"), ModeLive, ModeSimulated)
+	}
+
+	url := os.Getenv("WEBHOOK_URL")
+	if mode == ModeLive && url == "" {
+		return nil, fmt.Errorf("WEBHOOK_URL is required in %s mode, set WEBHOOK_MODE=%s to deliver without a webhook", ModeLive, ModeSimulated)
+	}
+
+	return &Sender{
+		URL:     url,
+		AuthKey: os.Getenv("WEBHOOK_AUTH_KEY"),
+		Mode:    mode,
+		Client:  &http.Client{Timeout: config.Duration("WEBHOOK_TIMEOUT", defaultTimeout)},
+	}, nil
+}
+
+// Send delivers the request and returns the parsed webhook response
+func (s *Sender) Send(ctx context.Context, request Request) (*Response, error) {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.NewWebhookError("Error marshaling request", err)
+	}
+
+	if s.Mode == ModeSimulated {
+		return s.simulate(jsonData), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, errors.NewWebhookError("Error creating request", err).
+			WithMetadata("webhookURL", s.URL)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	if s.AuthKey != "" {
+		req.Header.Add("x-ins-auth-key", s.AuthKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, errors.NewWebhookError("Error sending request", err).
+			WithMetadata("webhookURL", s.URL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, errors.NewWebhookError("Webhook request failed", fmt.Errorf("status code: %d", resp.StatusCode)).
+			WithMetadata("webhookURL", s.URL).
+			WithMetadata("statusCode", resp.StatusCode).
+			WithMetadata("body", string(body))
+	}
+
+	var response Response
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.NewWebhookError("Error decoding response", err).
+			WithMetadata("statusCode", resp.StatusCode)
+	}
+
+	if response.MessageID == "" {
+		return nil, errors.NewWebhookError("Webhook response has no messageId", nil).
+			WithMetadata("statusCode", resp.StatusCode).
+			WithMetadata("message", response.Message)
+	}
+
+	log.Printf("Webhook response - Status: %d, MessageID: %s", resp.StatusCode, response.MessageID)
+	return &response, nil
+}
+
+// simulate builds a successful response without calling the webhook
+func (s *Sender) simulate(body []byte) *Response {
+	response := &Response{
+		Message:   "Message sent successfully",
+		MessageID: fmt.Sprintf("SIMULATED_MSG_%d", time.Now().UnixNano()),
+	}
+	log.Printf("Simulated webhook request - Body: %s, MessageID: %s", string(body), response.MessageID)
+	return response
+}